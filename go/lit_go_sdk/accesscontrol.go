@@ -0,0 +1,847 @@
+package lit_go_sdk
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// The condition types of unified access control conditions
+const (
+	conditionTypeEvmBasic    = "evmBasic"
+	conditionTypeEvmContract = "evmContract"
+	conditionTypeSolRpc      = "solRpc"
+	conditionTypeCosmos      = "cosmos"
+)
+
+// Comparators of a ReturnValueTest
+const (
+	ComparatorEqual          = "="
+	ComparatorGreater        = ">"
+	ComparatorGreaterOrEqual = ">="
+	ComparatorLess           = "<"
+	ComparatorLessOrEqual    = "<="
+	ComparatorContains       = "contains"
+)
+
+// UserAddress is replaced by the nodes with the address of the user
+// requesting decryption, in condition parameters and return value tests
+const UserAddress = ":userAddress"
+
+var (
+	comparators = []string{ComparatorEqual, ComparatorGreater, ComparatorGreaterOrEqual, ComparatorLess, ComparatorLessOrEqual, ComparatorContains}
+
+	// standardContractTypes are the contract types the nodes know for EVM basic conditions
+	standardContractTypes = []string{"", "ERC20", "ERC721", "ERC721MetadataName", "ERC1155", "CASK", "Creaton", "POAP", "timestamp", "MolochDAOv2.1", "ProofOfHumanity", "SIWE", "PKPPermissions", "LitAction"}
+
+	evmAddressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+)
+
+// Condition is an element of a list of access control conditions: a
+// condition of one of the families, an Operator joining two elements, a Group
+// of elements, or a RawCondition
+type Condition interface {
+	// conditionType is the family of the condition, empty for operators,
+	// groups and raw conditions, which can be in any list
+	conditionType() string
+	// canonical returns the value the JS SDK hashes for the condition, with
+	// the condition type included if unified
+	canonical(unified bool) (interface{}, error)
+	validate() error
+}
+
+// ReturnValueTest compares the result of a condition to a value
+type ReturnValueTest struct {
+	// Key is the field of the result to compare, for EVM contract, Solana
+	// and Cosmos conditions. Empty compares the whole result.
+	Key string
+	// Comparator is one of the Comparator constants
+	Comparator string
+	// Value is the value to compare the result to, e.g. an amount or UserAddress
+	Value string
+}
+
+// validate checks the comparator is known
+func (t ReturnValueTest) validate() error {
+	for _, comparator := range comparators {
+		if t.Comparator == comparator {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown comparator %q", t.Comparator)
+}
+
+// keyed is the return value test with its key, as the conditions other than
+// EVM basic ones have it
+func (t ReturnValueTest) keyed() interface{} {
+	return struct {
+		Key        string `json:"key"`
+		Comparator string `json:"comparator"`
+		Value      string `json:"value"`
+	}{t.Key, t.Comparator, t.Value}
+}
+
+// EvmBasicCondition checks the result of a standard call on an EVM chain,
+// such as an ERC20 balance. It goes in AccessControlConditions.
+type EvmBasicCondition struct {
+	// ContractAddress is the contract called, empty for conditions on the
+	// user's wallet address or the chain itself
+	ContractAddress string
+	// StandardContractType is the kind of contract, e.g. ERC20, or empty
+	StandardContractType string
+	Chain                Chain
+	// Method is the method called, e.g. balanceOf
+	Method string
+	// Parameters are the arguments of the method
+	Parameters []string
+	// ReturnValueTest checks the result. Key is not used.
+	ReturnValueTest ReturnValueTest
+}
+
+func (c EvmBasicCondition) conditionType() string { return conditionTypeEvmBasic }
+
+func (c EvmBasicCondition) canonical(unified bool) (interface{}, error) {
+	return struct {
+		ConditionType        string      `json:"conditionType,omitempty"`
+		ContractAddress      string      `json:"contractAddress"`
+		Chain                Chain       `json:"chain"`
+		StandardContractType string      `json:"standardContractType"`
+		Method               string      `json:"method"`
+		Parameters           []string    `json:"parameters"`
+		ReturnValueTest      interface{} `json:"returnValueTest"`
+	}{
+		unifiedType(unified, conditionTypeEvmBasic),
+		c.ContractAddress, c.Chain, c.StandardContractType, c.Method, nonNil(c.Parameters),
+		struct {
+			Comparator string `json:"comparator"`
+			Value      string `json:"value"`
+		}{c.ReturnValueTest.Comparator, c.ReturnValueTest.Value},
+	}, nil
+}
+
+func (c EvmBasicCondition) validate() error {
+	if c.Chain == "" {
+		return fmt.Errorf("EVM basic condition without a chain")
+	}
+	if c.ContractAddress != "" && !evmAddressPattern.MatchString(c.ContractAddress) {
+		return fmt.Errorf("invalid contract address %q", c.ContractAddress)
+	}
+	if !containsString(standardContractTypes, c.StandardContractType) {
+		return fmt.Errorf("unknown standard contract type %q", c.StandardContractType)
+	}
+	if c.ReturnValueTest.Key != "" {
+		return fmt.Errorf("EVM basic conditions do not take a return value test key")
+	}
+	return c.ReturnValueTest.validate()
+}
+
+// MarshalJSON implements json.Marshaler, with the fields in canonical order
+func (c EvmBasicCondition) MarshalJSON() ([]byte, error) {
+	return marshalCanonical(c, false)
+}
+
+// EvmContractCondition checks the result of calling any contract function on
+// an EVM chain. It goes in EvmContractConditions.
+type EvmContractCondition struct {
+	ContractAddress string
+	FunctionName    string
+	// FunctionParams are the arguments of the function
+	FunctionParams []string
+	FunctionAbi    FunctionAbi
+	Chain          Chain
+	// ReturnValueTest checks the output of the function named Key, or the
+	// only output if Key is empty
+	ReturnValueTest ReturnValueTest
+}
+
+// FunctionAbi is the ABI of the function of an EvmContractCondition
+type FunctionAbi struct {
+	Name            string
+	Inputs          []AbiParam
+	Outputs         []AbiParam
+	Constant        bool
+	StateMutability string
+}
+
+// AbiParam is an input or output of a FunctionAbi
+type AbiParam struct {
+	Name string
+	Type string
+}
+
+func (c EvmContractCondition) conditionType() string { return conditionTypeEvmContract }
+
+func (c EvmContractCondition) canonical(unified bool) (interface{}, error) {
+	type abiParam struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+	params := func(in []AbiParam) []abiParam {
+		out := make([]abiParam, len(in))
+		for i, p := range in {
+			out[i] = abiParam{p.Name, p.Type}
+		}
+		return out
+	}
+	return struct {
+		ConditionType   string      `json:"conditionType,omitempty"`
+		ContractAddress string      `json:"contractAddress"`
+		FunctionName    string      `json:"functionName"`
+		FunctionParams  []string    `json:"functionParams"`
+		FunctionAbi     interface{} `json:"functionAbi"`
+		Chain           Chain       `json:"chain"`
+		ReturnValueTest interface{} `json:"returnValueTest"`
+	}{
+		unifiedType(unified, conditionTypeEvmContract),
+		c.ContractAddress, c.FunctionName, nonNil(c.FunctionParams),
+		struct {
+			Name            string     `json:"name"`
+			Inputs          []abiParam `json:"inputs"`
+			Outputs         []abiParam `json:"outputs"`
+			Constant        bool       `json:"constant"`
+			StateMutability string     `json:"stateMutability"`
+		}{c.FunctionAbi.Name, params(c.FunctionAbi.Inputs), params(c.FunctionAbi.Outputs), c.FunctionAbi.Constant, c.FunctionAbi.StateMutability},
+		c.Chain, c.ReturnValueTest.keyed(),
+	}, nil
+}
+
+func (c EvmContractCondition) validate() error {
+	if c.Chain == "" {
+		return fmt.Errorf("EVM contract condition without a chain")
+	}
+	if !evmAddressPattern.MatchString(c.ContractAddress) {
+		return fmt.Errorf("invalid contract address %q", c.ContractAddress)
+	}
+	if c.FunctionName == "" || c.FunctionAbi.Name != c.FunctionName {
+		return fmt.Errorf("function ABI name %q does not match function name %q", c.FunctionAbi.Name, c.FunctionName)
+	}
+	if len(c.FunctionParams) != len(c.FunctionAbi.Inputs) {
+		return fmt.Errorf("%s takes %d parameters, got %d", c.FunctionName, len(c.FunctionAbi.Inputs), len(c.FunctionParams))
+	}
+	if len(c.FunctionAbi.Outputs) == 0 {
+		return fmt.Errorf("function ABI of %s has no outputs", c.FunctionName)
+	}
+	return c.ReturnValueTest.validate()
+}
+
+// MarshalJSON implements json.Marshaler, with the fields in canonical order
+func (c EvmContractCondition) MarshalJSON() ([]byte, error) {
+	return marshalCanonical(c, false)
+}
+
+// SolRpcCondition checks the result of a Solana RPC call. It goes in
+// SolRpcConditions.
+type SolRpcCondition struct {
+	// Method is the RPC method, e.g. getBalance
+	Method string
+	Params []string
+	// PdaParams, PdaInterface and PdaKey read a field of a program derived
+	// account. They can be left empty.
+	PdaParams    []string
+	PdaInterface PdaInterface
+	PdaKey       string
+	Chain        Chain
+	// ReturnValueTest checks the field Key of the result
+	ReturnValueTest ReturnValueTest
+}
+
+// PdaInterface describes the layout of a program derived account
+type PdaInterface struct {
+	Offset int
+	Fields PdaFields
+}
+
+// PdaFields are the fields of a program derived account in their order. The
+// JS SDK hashes them in that order, which a map would lose.
+type PdaFields []PdaField
+
+// PdaField is a field of a program derived account and its size in bytes
+type PdaField struct {
+	Name string
+	Size int
+}
+
+// MarshalJSON implements json.Marshaler, encoding the fields as an object
+// with the keys in order
+func (f PdaFields) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	buf.WriteByte('{')
+	for i, field := range f {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := encoder.Encode(field.Name); err != nil {
+			return nil, err
+		}
+		buf.Truncate(buf.Len() - 1) // the newline Encode adds
+		buf.WriteString(":" + strconv.Itoa(field.Size))
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping the keys in order
+func (f *PdaFields) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return fmt.Errorf("PDA fields are not a JSON object")
+	}
+	fields := PdaFields{}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		var size int
+		if err := decoder.Decode(&size); err != nil {
+			return fmt.Errorf("PDA field %s: %w", token, err)
+		}
+		fields = append(fields, PdaField{Name: token.(string), Size: size})
+	}
+	*f = fields
+	return nil
+}
+
+func (c SolRpcCondition) conditionType() string { return conditionTypeSolRpc }
+
+func (c SolRpcCondition) canonical(unified bool) (interface{}, error) {
+	return struct {
+		ConditionType string   `json:"conditionType,omitempty"`
+		Method        string   `json:"method"`
+		Params        []string `json:"params"`
+		PdaParams     []string `json:"pdaParams"`
+		PdaInterface  struct {
+			Offset int       `json:"offset"`
+			Fields PdaFields `json:"fields"`
+		} `json:"pdaInterface"`
+		PdaKey          string      `json:"pdaKey"`
+		Chain           Chain       `json:"chain"`
+		ReturnValueTest interface{} `json:"returnValueTest"`
+	}{
+		ConditionType: unifiedType(unified, conditionTypeSolRpc),
+		Method:        c.Method,
+		Params:        nonNil(c.Params),
+		PdaParams:     nonNil(c.PdaParams),
+		PdaInterface: struct {
+			Offset int       `json:"offset"`
+			Fields PdaFields `json:"fields"`
+		}{c.PdaInterface.Offset, c.PdaInterface.Fields},
+		PdaKey:          c.PdaKey,
+		Chain:           c.Chain,
+		ReturnValueTest: c.ReturnValueTest.keyed(),
+	}, nil
+}
+
+func (c SolRpcCondition) validate() error {
+	if c.Chain == "" {
+		return fmt.Errorf("Solana RPC condition without a chain")
+	}
+	if c.Method == "" {
+		return fmt.Errorf("Solana RPC condition without a method")
+	}
+	return c.ReturnValueTest.validate()
+}
+
+// MarshalJSON implements json.Marshaler, with the fields in canonical order
+func (c SolRpcCondition) MarshalJSON() ([]byte, error) {
+	return marshalCanonical(c, false)
+}
+
+// CosmosCondition checks the result of a Cosmos REST API call. It can only
+// be used in UnifiedAccessControlConditions.
+type CosmosCondition struct {
+	// Path is the REST path queried, e.g. /cosmos/bank/v1beta1/balances/:userAddress
+	Path  string
+	Chain Chain
+	// Method and Parameters are only used by some paths and can be left empty
+	Method          string
+	Parameters      []string
+	ReturnValueTest ReturnValueTest
+}
+
+func (c CosmosCondition) conditionType() string { return conditionTypeCosmos }
+
+func (c CosmosCondition) canonical(unified bool) (interface{}, error) {
+	return struct {
+		ConditionType   string      `json:"conditionType,omitempty"`
+		Path            string      `json:"path"`
+		Chain           Chain       `json:"chain"`
+		Method          string      `json:"method,omitempty"`
+		Parameters      []string    `json:"parameters,omitempty"`
+		ReturnValueTest interface{} `json:"returnValueTest"`
+	}{unifiedType(unified, conditionTypeCosmos), c.Path, c.Chain, c.Method, c.Parameters, c.ReturnValueTest.keyed()}, nil
+}
+
+func (c CosmosCondition) validate() error {
+	if c.Chain == "" {
+		return fmt.Errorf("Cosmos condition without a chain")
+	}
+	if c.Path == "" {
+		return fmt.Errorf("Cosmos condition without a path")
+	}
+	return c.ReturnValueTest.validate()
+}
+
+// MarshalJSON implements json.Marshaler, with the fields in canonical order
+func (c CosmosCondition) MarshalJSON() ([]byte, error) {
+	return marshalCanonical(c, false)
+}
+
+// Operator joins the conditions before and after it
+type Operator string
+
+const (
+	// And requires both conditions to be met
+	And Operator = "and"
+	// Or requires either condition to be met
+	Or Operator = "or"
+)
+
+func (o Operator) conditionType() string { return "" }
+
+func (o Operator) canonical(bool) (interface{}, error) {
+	return struct {
+		Operator string `json:"operator"`
+	}{string(o)}, nil
+}
+
+func (o Operator) validate() error {
+	if o != And && o != Or {
+		return fmt.Errorf("unknown operator %q", string(o))
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (o Operator) MarshalJSON() ([]byte, error) {
+	return marshalCanonical(o, false)
+}
+
+// Group is a parenthesized list of conditions joined by operators, e.g. for
+// a AND (b OR c)
+type Group []Condition
+
+func (g Group) conditionType() string { return "" }
+
+func (g Group) canonical(unified bool) (interface{}, error) {
+	return canonicalList(g, unified)
+}
+
+func (g Group) validate() error {
+	return validateList(g, "")
+}
+
+// RawCondition is a JSON object for a condition the typed ones cannot
+// express. It is sent with its keys in the order given, only compacted, and
+// must include conditionType in unified conditions.
+type RawCondition json.RawMessage
+
+func (r RawCondition) conditionType() string { return "" }
+
+func (r RawCondition) canonical(bool) (interface{}, error) {
+	return json.RawMessage(r), nil
+}
+
+func (r RawCondition) validate() error {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(r, &object); err != nil || object == nil {
+		return fmt.Errorf("raw condition is not a JSON object")
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (r RawCondition) MarshalJSON() ([]byte, error) {
+	return marshalCanonical(r, false)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, keeping a copy of data
+func (r *RawCondition) UnmarshalJSON(data []byte) error {
+	*r = append((*r)[:0], data...)
+	return nil
+}
+
+// Conditions is a list of access control conditions of one family, joined by
+// operators. It marshals to the canonical JSON the JS SDK hashes.
+type Conditions []Condition
+
+// MarshalJSON implements json.Marshaler
+func (c Conditions) MarshalJSON() ([]byte, error) {
+	return marshalCanonical(Group(c), false)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. See decodeCondition for how the
+// elements are decoded.
+func (c *Conditions) UnmarshalJSON(data []byte) error {
+	group, err := decodeList(data, false)
+	if err != nil {
+		return err
+	}
+	*c = Conditions(group)
+	return nil
+}
+
+// Hash returns the hex encoded SHA-256 hash of the canonical JSON, which the
+// JS SDK uses to identify the conditions in lit-accesscontrolcondition resources
+func (c Conditions) Hash() (string, error) {
+	return hashCanonical(Group(c), false)
+}
+
+// UnifiedConditions is a list of access control conditions of any families,
+// joined by operators. Each condition is marshalled with its conditionType.
+type UnifiedConditions []Condition
+
+// MarshalJSON implements json.Marshaler
+func (c UnifiedConditions) MarshalJSON() ([]byte, error) {
+	return marshalCanonical(Group(c), true)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, like Conditions.UnmarshalJSON
+func (c *UnifiedConditions) UnmarshalJSON(data []byte) error {
+	group, err := decodeList(data, true)
+	if err != nil {
+		return err
+	}
+	*c = UnifiedConditions(group)
+	return nil
+}
+
+// Hash returns the hex encoded SHA-256 hash of the canonical JSON, like
+// Conditions.Hash
+func (c UnifiedConditions) Hash() (string, error) {
+	return hashCanonical(Group(c), true)
+}
+
+// WalletAddressCondition is met by the wallet with address on chain
+func WalletAddressCondition(chain Chain, address string) EvmBasicCondition {
+	return EvmBasicCondition{
+		Chain:           chain,
+		Parameters:      []string{UserAddress},
+		ReturnValueTest: ReturnValueTest{Comparator: ComparatorEqual, Value: address},
+	}
+}
+
+// ERC20BalanceCondition is met by wallets whose balance of the ERC20 token
+// contract compares to amount, in the token's smallest unit
+func ERC20BalanceCondition(chain Chain, contract, comparator, amount string) EvmBasicCondition {
+	return EvmBasicCondition{
+		ContractAddress:      contract,
+		StandardContractType: "ERC20",
+		Chain:                chain,
+		Method:               "balanceOf",
+		Parameters:           []string{UserAddress},
+		ReturnValueTest:      ReturnValueTest{Comparator: comparator, Value: amount},
+	}
+}
+
+// ERC721BalanceCondition is met by wallets whose number of NFTs of the
+// ERC721 contract compares to count
+func ERC721BalanceCondition(chain Chain, contract, comparator, count string) EvmBasicCondition {
+	return EvmBasicCondition{
+		ContractAddress:      contract,
+		StandardContractType: "ERC721",
+		Chain:                chain,
+		Method:               "balanceOf",
+		Parameters:           []string{UserAddress},
+		ReturnValueTest:      ReturnValueTest{Comparator: comparator, Value: count},
+	}
+}
+
+// ERC721OwnershipCondition is met by the owner of the NFT tokenID of the
+// ERC721 contract
+func ERC721OwnershipCondition(chain Chain, contract, tokenID string) EvmBasicCondition {
+	return EvmBasicCondition{
+		ContractAddress:      contract,
+		StandardContractType: "ERC721",
+		Chain:                chain,
+		Method:               "ownerOf",
+		Parameters:           []string{tokenID},
+		ReturnValueTest:      ReturnValueTest{Comparator: ComparatorEqual, Value: UserAddress},
+	}
+}
+
+// ERC1155BalanceCondition is met by wallets whose balance of the token
+// tokenID of the ERC1155 contract compares to amount
+func ERC1155BalanceCondition(chain Chain, contract, tokenID, comparator, amount string) EvmBasicCondition {
+	return EvmBasicCondition{
+		ContractAddress:      contract,
+		StandardContractType: "ERC1155",
+		Chain:                chain,
+		Method:               "balanceOf",
+		Parameters:           []string{UserAddress, tokenID},
+		ReturnValueTest:      ReturnValueTest{Comparator: comparator, Value: amount},
+	}
+}
+
+// TimeLockCondition is met once the latest block of chain is at least as
+// recent as t
+func TimeLockCondition(chain Chain, t time.Time) EvmBasicCondition {
+	return EvmBasicCondition{
+		StandardContractType: "timestamp",
+		Chain:                chain,
+		Method:               "eth_getBlockByNumber",
+		Parameters:           []string{"latest"},
+		ReturnValueTest:      ReturnValueTest{Comparator: ComparatorGreaterOrEqual, Value: strconv.FormatInt(t.Unix(), 10)},
+	}
+}
+
+// PKPOwnershipCondition is met by the owner of the PKP tokenID, given the
+// address of the PKP NFT contract of the Lit network
+func PKPOwnershipCondition(pkpNFTContract, tokenID string) EvmBasicCondition {
+	return ERC721OwnershipCondition(ChainYellowstone, pkpNFTContract, tokenID)
+}
+
+// PKPPermittedAddressCondition is met by the addresses permitted to use the
+// PKP tokenID
+func PKPPermittedAddressCondition(tokenID string) EvmBasicCondition {
+	return EvmBasicCondition{
+		StandardContractType: "PKPPermissions",
+		Chain:                ChainYellowstone,
+		Method:               "isPermittedAddress",
+		Parameters:           []string{tokenID, UserAddress},
+		ReturnValueTest:      ReturnValueTest{Comparator: ComparatorEqual, Value: "true"},
+	}
+}
+
+// decodeList decodes a JSON array of conditions, operators and nested arrays
+func decodeList(data []byte, unified bool) (Group, error) {
+	var elements []json.RawMessage
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return nil, err
+	}
+	if elements == nil {
+		return nil, nil
+	}
+	group := make(Group, len(elements))
+	for i, element := range elements {
+		condition, err := decodeCondition(element, unified)
+		if err != nil {
+			return nil, fmt.Errorf("condition at %d: %w", i, err)
+		}
+		group[i] = condition
+	}
+	return group, nil
+}
+
+// decodeCondition decodes an element of a list of conditions: an array is a
+// Group, and an object is an Operator or a condition of the family its keys
+// identify. The typed value is only used if it marshals back to the same
+// JSON value, so no field is lost; anything else is a RawCondition. Key order
+// only matters to the JS SDK in pdaInterface.fields, which PdaFields keeps.
+func decodeCondition(data json.RawMessage, unified bool) (Condition, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		return decodeList(data, unified)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		return nil, fmt.Errorf("not a JSON object or array: %s", data)
+	}
+
+	var typed Condition
+	var err error
+	has := func(key string) bool { _, ok := fields[key]; return ok }
+	switch {
+	case has("operator") && len(fields) == 1:
+		var o struct{ Operator string }
+		err = json.Unmarshal(data, &o)
+		typed = Operator(o.Operator)
+	case has("functionAbi"):
+		var c EvmContractCondition
+		err = json.Unmarshal(data, &c)
+		typed = c
+	case has("pdaInterface"):
+		var c SolRpcCondition
+		err = json.Unmarshal(data, &c)
+		typed = c
+	case has("path"):
+		var c CosmosCondition
+		err = json.Unmarshal(data, &c)
+		typed = c
+	case has("standardContractType"):
+		var c EvmBasicCondition
+		err = json.Unmarshal(data, &c)
+		typed = c
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return nil, err
+	}
+	if typed != nil && err == nil {
+		if encoded, err := marshalCanonical(typed, unified); err == nil && sameJSON(encoded, data) {
+			return typed, nil
+		}
+	}
+	return RawCondition(compact.Bytes()), nil
+}
+
+// sameJSON reports whether a and b encode the same JSON value
+func sameJSON(a, b []byte) bool {
+	var va, vb interface{}
+	for _, v := range []struct {
+		data  []byte
+		value *interface{}
+	}{{a, &va}, {b, &vb}} {
+		decoder := json.NewDecoder(bytes.NewReader(v.data))
+		decoder.UseNumber()
+		if err := decoder.Decode(v.value); err != nil {
+			return false
+		}
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// validateConditions checks the structure of a list and that its conditions
+// are valid and of the family conditionType, or of any family if it is empty
+func validateConditions(name string, conditions []Condition, conditionType string) error {
+	if err := validateList(conditions, conditionType); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidParams, name, err)
+	}
+	return nil
+}
+
+// validateList checks a list alternates between conditions and operators
+func validateList(conditions []Condition, conditionType string) error {
+	if len(conditions) == 0 {
+		return fmt.Errorf("empty list of conditions")
+	}
+	for i, condition := range conditions {
+		_, isOperator := condition.(Operator)
+		if isOperator != (i%2 == 1) {
+			if isOperator {
+				return fmt.Errorf("operator %q at %d does not follow a condition", condition, i)
+			}
+			return fmt.Errorf("conditions at %d and %d are not joined by an operator", i-1, i)
+		}
+		if group, ok := condition.(Group); ok {
+			if err := validateList(group, conditionType); err != nil {
+				return fmt.Errorf("group at %d: %v", i, err)
+			}
+			continue
+		}
+		if t := condition.conditionType(); t != "" && conditionType != "" && t != conditionType {
+			return fmt.Errorf("%s condition at %d in a list of %s conditions", t, i, conditionType)
+		}
+		if err := condition.validate(); err != nil {
+			return fmt.Errorf("condition at %d: %v", i, err)
+		}
+	}
+	if len(conditions)%2 == 0 {
+		return fmt.Errorf("list ends with an operator")
+	}
+	return nil
+}
+
+// canonicalList returns the canonical values of a list
+func canonicalList(conditions []Condition, unified bool) (interface{}, error) {
+	out := make([]interface{}, len(conditions))
+	for i, condition := range conditions {
+		value, err := condition.canonical(unified)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = value
+	}
+	return out, nil
+}
+
+// marshalCanonical encodes the canonical value of c like JSON.stringify:
+// compact and without escaping <, > and &
+func marshalCanonical(c Condition, unified bool) ([]byte, error) {
+	value, err := c.canonical(unified)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// hashCanonical returns the hex encoded SHA-256 hash of the canonical JSON of c
+func hashCanonical(c Condition, unified bool) (string, error) {
+	data, err := marshalCanonical(c, unified)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// unifiedType returns conditionType if unified, or empty to leave it out
+func unifiedType(unified bool, conditionType string) string {
+	if unified {
+		return conditionType
+	}
+	return ""
+}
+
+// nonNil returns s, or an empty slice so it marshals to [] like in JS
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+func containsString(values []string, s string) bool {
+	for _, value := range values {
+		if value == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the access control conditions. EncryptString calls it
+// before sending anything to the server; the error wraps ErrInvalidParams.
+func (p EncryptStringParams) Validate() error {
+	return validateConditionLists(p.AccessControlConditions, p.EvmContractConditions, p.SolRpcConditions, p.UnifiedAccessControlConditions)
+}
+
+// Validate checks the access control conditions. DecryptString calls it
+// before sending anything to the server; the error wraps ErrInvalidParams.
+func (p DecryptStringParams) Validate() error {
+	return validateConditionLists(p.AccessControlConditions, p.EvmContractConditions, p.SolRpcConditions, p.UnifiedAccessControlConditions)
+}
+
+// validateConditionLists checks the lists of conditions of encryption
+// parameters, of which at least one must be set
+func validateConditionLists(accessControl, evmContract, solRpc Conditions, unified UnifiedConditions) error {
+	lists := []struct {
+		name          string
+		conditions    []Condition
+		conditionType string
+	}{
+		{"AccessControlConditions", accessControl, conditionTypeEvmBasic},
+		{"EvmContractConditions", evmContract, conditionTypeEvmContract},
+		{"SolRpcConditions", solRpc, conditionTypeSolRpc},
+		{"UnifiedAccessControlConditions", unified, ""},
+	}
+	set := false
+	for _, list := range lists {
+		if list.conditions == nil {
+			continue
+		}
+		set = true
+		if err := validateConditions(list.name, list.conditions, list.conditionType); err != nil {
+			return err
+		}
+	}
+	if !set {
+		return fmt.Errorf("%w: no access control conditions", ErrInvalidParams)
+	}
+	return nil
+}
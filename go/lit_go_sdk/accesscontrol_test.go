@@ -0,0 +1,261 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const conditionsTestAddress = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+
+func TestConditions_CanonicalJSON(t *testing.T) {
+	conditions := Conditions{WalletAddressCondition(ChainEthereum, conditionsTestAddress)}
+
+	data, err := json.Marshal(conditions)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `[{"contractAddress":"","chain":"ethereum","standardContractType":"","method":"","parameters":[":userAddress"],"returnValueTest":{"comparator":"=","value":"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"}}]`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	// The SHA-256 of the JSON.stringify output of the JS canonical formatter
+	hash, err := conditions.Hash()
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	if want := "4100e40fbb3720c3b61d2330a20052d97d1e40605ee610d1201bbcb59548ee00"; hash != want {
+		t.Errorf("Hash() = %s, want %s", hash, want)
+	}
+}
+
+func TestUnifiedConditions_CanonicalJSON(t *testing.T) {
+	conditions := UnifiedConditions{
+		TimeLockCondition(ChainEthereum, time.Unix(1700000000, 0)),
+		Or,
+		Group{
+			SolRpcCondition{
+				Method:          "getBalance",
+				Params:          []string{UserAddress},
+				Chain:           "solana",
+				ReturnValueTest: ReturnValueTest{Key: "", Comparator: ComparatorGreaterOrEqual, Value: "100000000"},
+			},
+			And,
+			CosmosCondition{
+				Path:            "/cosmos/bank/v1beta1/balances/:userAddress",
+				Chain:           "cosmos",
+				ReturnValueTest: ReturnValueTest{Key: "$.balances[0].amount", Comparator: ComparatorGreaterOrEqual, Value: "1000000"},
+			},
+		},
+	}
+	if err := validateConditions("UnifiedAccessControlConditions", conditions, ""); err != nil {
+		t.Fatalf("validateConditions() error = %v", err)
+	}
+
+	// json.Marshal escapes > in the output of MarshalJSON, so compare the
+	// bytes Hash uses
+	data, err := marshalCanonical(Group(conditions), true)
+	if err != nil {
+		t.Fatalf("marshalCanonical() error = %v", err)
+	}
+	want := `[{"conditionType":"evmBasic","contractAddress":"","chain":"ethereum","standardContractType":"timestamp","method":"eth_getBlockByNumber","parameters":["latest"],"returnValueTest":{"comparator":">=","value":"1700000000"}},` +
+		`{"operator":"or"},` +
+		`[{"conditionType":"solRpc","method":"getBalance","params":[":userAddress"],"pdaParams":[],"pdaInterface":{"offset":0,"fields":{}},"pdaKey":"","chain":"solana","returnValueTest":{"key":"","comparator":">=","value":"100000000"}},` +
+		`{"operator":"and"},` +
+		`{"conditionType":"cosmos","path":"/cosmos/bank/v1beta1/balances/:userAddress","chain":"cosmos","returnValueTest":{"key":"$.balances[0].amount","comparator":">=","value":"1000000"}}]]`
+	if string(data) != want {
+		t.Errorf("marshalCanonical() = %s, want %s", data, want)
+	}
+}
+
+func TestSolRpcCondition_PdaFieldsOrder(t *testing.T) {
+	raw := `{"method":"getAccountInfo","params":[":userAddress"],"pdaParams":["seed"],"pdaInterface":{"offset":8,"fields":{"owner":32,"amount":8,"bump":1}},"pdaKey":"amount","chain":"solana","returnValueTest":{"key":"","comparator":">","value":"0"}}`
+	condition := SolRpcCondition{
+		Method:          "getAccountInfo",
+		Params:          []string{UserAddress},
+		PdaParams:       []string{"seed"},
+		PdaInterface:    PdaInterface{Offset: 8, Fields: PdaFields{{"owner", 32}, {"amount", 8}, {"bump", 1}}},
+		PdaKey:          "amount",
+		Chain:           "solana",
+		ReturnValueTest: ReturnValueTest{Comparator: ComparatorGreater, Value: "0"},
+	}
+
+	// The fields keep their order, like in JS
+	data, err := marshalCanonical(condition, false)
+	if err != nil {
+		t.Fatalf("marshalCanonical() error = %v", err)
+	}
+	if string(data) != raw {
+		t.Errorf("marshalCanonical() = %s, want %s", data, raw)
+	}
+
+	var decoded Conditions
+	if err := json.Unmarshal([]byte("["+raw+"]"), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, Conditions{condition}) {
+		t.Errorf("json.Unmarshal() = %#v, want %#v", decoded, Conditions{condition})
+	}
+	rawHash, _ := Conditions{RawCondition(raw)}.Hash()
+	if hash, _ := decoded.Hash(); hash != rawHash {
+		t.Errorf("Hash() = %s, want the hash of the raw condition %s", hash, rawHash)
+	}
+}
+
+func TestEvmContractCondition_CanonicalJSON(t *testing.T) {
+	condition := EvmContractCondition{
+		ContractAddress: "0xb71a679cfff330591d556c4b9f21c7739ca9590c",
+		FunctionName:    "members",
+		FunctionParams:  []string{UserAddress},
+		FunctionAbi: FunctionAbi{
+			Name:            "members",
+			Inputs:          []AbiParam{{Name: "", Type: "address"}},
+			Outputs:         []AbiParam{{Name: "delegateKey", Type: "address"}, {Name: "shares", Type: "uint256"}},
+			Constant:        true,
+			StateMutability: "view",
+		},
+		Chain:           ChainXdai,
+		ReturnValueTest: ReturnValueTest{Key: "shares", Comparator: ComparatorGreaterOrEqual, Value: "1"},
+	}
+
+	data, err := marshalCanonical(condition, false)
+	if err != nil {
+		t.Fatalf("marshalCanonical() error = %v", err)
+	}
+	want := `{"contractAddress":"0xb71a679cfff330591d556c4b9f21c7739ca9590c","functionName":"members","functionParams":[":userAddress"],` +
+		`"functionAbi":{"name":"members","inputs":[{"name":"","type":"address"}],"outputs":[{"name":"delegateKey","type":"address"},{"name":"shares","type":"uint256"}],"constant":true,"stateMutability":"view"},` +
+		`"chain":"xdai","returnValueTest":{"key":"shares","comparator":">=","value":"1"}}`
+	if string(data) != want {
+		t.Errorf("marshalCanonical() = %s, want %s", data, want)
+	}
+}
+
+func TestConditions_UnmarshalJSON(t *testing.T) {
+	erc20 := ERC20BalanceCondition(ChainPolygon, "0x7ceB23fD6bC0adD59E62ac25578270cFf1b9f619", ComparatorGreater, "0")
+	conditions := Conditions{
+		WalletAddressCondition(ChainEthereum, conditionsTestAddress),
+		Or,
+		Group{erc20, And, RawCondition(`{"zeta":1,"alpha":{"b":2,"a":1}}`)},
+	}
+	data, err := json.Marshal(conditions)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var decoded Conditions
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, conditions) {
+		t.Errorf("json.Unmarshal() = %#v, want %#v", decoded, conditions)
+	}
+	// The raw condition keeps its keys in order
+	if again, _ := json.Marshal(decoded); string(again) != string(data) {
+		t.Errorf("json.Marshal() of the decoded conditions = %s, want %s", again, data)
+	}
+
+	// A condition with fields the typed ones do not have is kept raw
+	raw := `[{"contractAddress":"","chain":"ethereum","standardContractType":"","method":"","parameters":[":userAddress"],"returnValueTest":{"comparator":"=","value":"0x1"},"extra":true}]`
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if _, ok := decoded[0].(RawCondition); !ok {
+		t.Errorf("Expected a RawCondition, got %T", decoded[0])
+	}
+	if again, _ := json.Marshal(decoded); string(again) != raw {
+		t.Errorf("json.Marshal() = %s, want %s", again, raw)
+	}
+
+	if err := json.Unmarshal([]byte(`["and"]`), &decoded); err == nil {
+		t.Error("Expected an error for an element that is not an object")
+	}
+}
+
+func TestUnifiedConditions_UnmarshalJSON(t *testing.T) {
+	conditions := UnifiedConditions{
+		TimeLockCondition(ChainEthereum, time.Unix(1700000000, 0)),
+		And,
+		CosmosCondition{
+			Path:            "/cosmos/bank/v1beta1/balances/:userAddress",
+			Chain:           "cosmos",
+			ReturnValueTest: ReturnValueTest{Key: "$.balances[0].amount", Comparator: ComparatorGreaterOrEqual, Value: "1000000"},
+		},
+	}
+	data, err := json.Marshal(conditions)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var decoded UnifiedConditions
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, conditions) {
+		t.Errorf("json.Unmarshal() = %#v, want %#v", decoded, conditions)
+	}
+}
+
+func TestEncryptStringParams_Validate(t *testing.T) {
+	erc20 := ERC20BalanceCondition(ChainPolygon, "0x7ceB23fD6bC0adD59E62ac25578270cFf1b9f619", ComparatorGreater, "0")
+	tests := []struct {
+		name    string
+		params  EncryptStringParams
+		wantErr bool
+	}{
+		{"wallet", EncryptStringParams{AccessControlConditions: Conditions{WalletAddressCondition(ChainEthereum, conditionsTestAddress)}}, false},
+		{"or", EncryptStringParams{AccessControlConditions: Conditions{erc20, Or, ERC721OwnershipCondition(ChainEthereum, "0x22C1f6050E56d2876009903609a2cC3fEf83B415", "42")}}, false},
+		{"pkp", EncryptStringParams{AccessControlConditions: Conditions{PKPOwnershipCondition("0x02C4242F72d62c8fEF2b2DB088A35a9F4ec741C7", "1"), And, PKPPermittedAddressCondition("1")}}, false},
+		{"raw", EncryptStringParams{UnifiedAccessControlConditions: UnifiedConditions{RawCondition(`{"conditionType":"evmBasic"}`)}}, false},
+		{"raw not an object", EncryptStringParams{UnifiedAccessControlConditions: UnifiedConditions{RawCondition(`["evmBasic"]`)}}, true},
+		{"no conditions", EncryptStringParams{}, true},
+		{"empty list", EncryptStringParams{AccessControlConditions: Conditions{}}, true},
+		{"missing operator", EncryptStringParams{AccessControlConditions: Conditions{erc20, erc20}}, true},
+		{"leading operator", EncryptStringParams{AccessControlConditions: Conditions{And, erc20}}, true},
+		{"trailing operator", EncryptStringParams{AccessControlConditions: Conditions{erc20, And}}, true},
+		{"unknown operator", EncryptStringParams{AccessControlConditions: Conditions{erc20, Operator("xor"), erc20}}, true},
+		{"wrong family", EncryptStringParams{EvmContractConditions: Conditions{erc20}}, true},
+		{"wrong family in group", EncryptStringParams{SolRpcConditions: Conditions{Group{erc20}}}, true},
+		{"bad address", EncryptStringParams{AccessControlConditions: Conditions{ERC20BalanceCondition(ChainEthereum, "0x1234", ComparatorGreater, "0")}}, true},
+		{"bad comparator", EncryptStringParams{AccessControlConditions: Conditions{ERC20BalanceCondition(ChainEthereum, conditionsTestAddress, "!=", "0")}}, true},
+		{"no chain", EncryptStringParams{AccessControlConditions: Conditions{WalletAddressCondition("", conditionsTestAddress)}}, true},
+		{"abi mismatch", EncryptStringParams{EvmContractConditions: Conditions{EvmContractCondition{
+			ContractAddress: conditionsTestAddress,
+			FunctionName:    "balanceOf",
+			FunctionParams:  []string{UserAddress, "1"},
+			FunctionAbi:     FunctionAbi{Name: "balanceOf", Inputs: []AbiParam{{Type: "address"}}, Outputs: []AbiParam{{Type: "uint256"}}},
+			Chain:           ChainEthereum,
+			ReturnValueTest: ReturnValueTest{Comparator: ComparatorGreater, Value: "0"},
+		}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("Expected errors.Is(err, ErrInvalidParams), got %v", err)
+			}
+		})
+	}
+}
+
+func TestDecryptStringContext_InvalidConditionsNotSent(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request to be sent")
+	}))
+
+	_, err := client.DecryptStringContext(context.Background(), DecryptStringParams{
+		AccessControlConditions: Conditions{WalletAddressCondition(ChainEthereum, conditionsTestAddress), And},
+		SessionSigs:             SessionSigs{"node": {}},
+	})
+	if !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package lit_go_sdk
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AuthMethod is a way of authenticating with the Lit network, such as a
+// wallet signature or an OAuth token
+type AuthMethod struct {
+	// AuthMethodType identifies the kind of auth method
+	AuthMethodType AuthMethodType `json:"authMethodType"`
+	// AccessToken is the credential, e.g. a JSON encoded auth sig
+	AccessToken string `json:"accessToken"`
+}
+
+// oauthAuthMethodTypes are the auth method types whose access token is an
+// OAuth access token or an OIDC JWT
+var oauthAuthMethodTypes = []AuthMethodType{
+	AuthMethodTypeDiscord,
+	AuthMethodTypeGoogle,
+	AuthMethodTypeGoogleJwt,
+	AuthMethodTypeAppleJwt,
+}
+
+// EthWalletAuthMethod returns the auth method of the wallet that signed
+// authSig, with the JSON encoded auth sig as access token
+func EthWalletAuthMethod(authSig AuthSig) AuthMethod {
+	// An AuthSig only holds strings, encoding it cannot fail
+	accessToken, _ := json.Marshal(authSig)
+	return AuthMethod{AuthMethodType: AuthMethodTypeEthWallet, AccessToken: string(accessToken)}
+}
+
+// LitActionAuthMethod returns an auth method checked by a Lit Action, which
+// gets accessToken as is
+func LitActionAuthMethod(accessToken string) AuthMethod {
+	return AuthMethod{AuthMethodType: AuthMethodTypeLitAction, AccessToken: accessToken}
+}
+
+// WebAuthnAuthMethod returns the auth method of a WebAuthn credential, the
+// JSON encoded PublicKeyCredential returned by the browser
+func WebAuthnAuthMethod(credential json.RawMessage) (AuthMethod, error) {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(credential, &fields); err != nil {
+		return AuthMethod{}, fmt.Errorf("%w: WebAuthn credential is not a JSON object: %v", ErrInvalidParams, err)
+	}
+	if _, ok := fields["rawId"].(string); !ok {
+		return AuthMethod{}, fmt.Errorf("%w: WebAuthn credential without a rawId", ErrInvalidParams)
+	}
+	return AuthMethod{AuthMethodType: AuthMethodTypeWebAuthn, AccessToken: string(credential)}, nil
+}
+
+// OAuthAuthMethod returns the auth method of an OAuth access token or OIDC
+// JWT. authMethodType is the provider: AuthMethodTypeDiscord,
+// AuthMethodTypeGoogle, AuthMethodTypeGoogleJwt or AuthMethodTypeAppleJwt.
+func OAuthAuthMethod(authMethodType AuthMethodType, token string) (AuthMethod, error) {
+	if !containsAuthMethodType(oauthAuthMethodTypes, authMethodType) {
+		return AuthMethod{}, fmt.Errorf("%w: %s is not an OAuth auth method type", ErrInvalidParams, authMethodType)
+	}
+	if token == "" {
+		return AuthMethod{}, fmt.Errorf("%w: empty %s token", ErrInvalidParams, authMethodType)
+	}
+	return AuthMethod{AuthMethodType: authMethodType, AccessToken: token}, nil
+}
+
+// AuthSig returns the auth sig of an EthWallet auth method
+func (m AuthMethod) AuthSig() (*AuthSig, error) {
+	if m.AuthMethodType != AuthMethodTypeEthWallet {
+		return nil, fmt.Errorf("%w: %s auth method has no auth sig", ErrInvalidParams, m.AuthMethodType)
+	}
+	var authSig AuthSig
+	if err := json.Unmarshal([]byte(m.AccessToken), &authSig); err != nil {
+		return nil, fmt.Errorf("%w: invalid auth sig in access token: %v", ErrInvalidParams, err)
+	}
+	return &authSig, nil
+}
+
+// Validate checks that the auth method has a type and an access token. The
+// error wraps ErrInvalidParams.
+func (m AuthMethod) Validate() error {
+	if m.AuthMethodType == 0 {
+		return fmt.Errorf("%w: auth method without a type", ErrInvalidParams)
+	}
+	if m.AccessToken == "" {
+		return fmt.Errorf("%w: %s auth method without an access token", ErrInvalidParams, m.AuthMethodType)
+	}
+	if m.AuthMethodType == AuthMethodTypeEthWallet {
+		if _, err := m.AuthSig(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// containsAuthMethodType reports whether types contains t
+func containsAuthMethodType(types []AuthMethodType, t AuthMethodType) bool {
+	for _, v := range types {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestAuthMethodConstructors(t *testing.T) {
+	signer, _ := PrivateKeySignerFromHex(signerTestKey)
+	authSig, err := NewAuthSig(context.Background(), signer, "hello")
+	if err != nil {
+		t.Fatalf("NewAuthSig() error = %v", err)
+	}
+
+	wallet := EthWalletAuthMethod(*authSig)
+	if wallet.AuthMethodType != AuthMethodTypeEthWallet {
+		t.Errorf("AuthMethodType = %s, want EthWallet", wallet.AuthMethodType)
+	}
+	decoded, err := wallet.AuthSig()
+	if err != nil || *decoded != *authSig {
+		t.Errorf("AuthSig() = %+v, %v, want %+v", decoded, err, authSig)
+	}
+
+	if m := LitActionAuthMethod("token"); m != (AuthMethod{AuthMethodType: AuthMethodTypeLitAction, AccessToken: "token"}) {
+		t.Errorf("LitActionAuthMethod() = %+v", m)
+	}
+	if _, err := LitActionAuthMethod("token").AuthSig(); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("AuthSig() of a Lit Action auth method error = %v, want ErrInvalidParams", err)
+	}
+
+	credential := json.RawMessage(`{"id":"abc","rawId":"abc","type":"public-key","response":{}}`)
+	webAuthn, err := WebAuthnAuthMethod(credential)
+	if err != nil || webAuthn.AuthMethodType != AuthMethodTypeWebAuthn || webAuthn.AccessToken != string(credential) {
+		t.Errorf("WebAuthnAuthMethod() = %+v, %v", webAuthn, err)
+	}
+	for _, invalid := range []string{`"abc"`, `{"id":"abc"}`, `{`} {
+		if _, err := WebAuthnAuthMethod(json.RawMessage(invalid)); !errors.Is(err, ErrInvalidParams) {
+			t.Errorf("WebAuthnAuthMethod(%s) error = %v, want ErrInvalidParams", invalid, err)
+		}
+	}
+
+	google, err := OAuthAuthMethod(AuthMethodTypeGoogleJwt, "eyJhbGciOi")
+	if err != nil || google != (AuthMethod{AuthMethodType: AuthMethodTypeGoogleJwt, AccessToken: "eyJhbGciOi"}) {
+		t.Errorf("OAuthAuthMethod() = %+v, %v", google, err)
+	}
+	if _, err := OAuthAuthMethod(AuthMethodTypeEthWallet, "token"); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("OAuthAuthMethod(EthWallet) error = %v, want ErrInvalidParams", err)
+	}
+	if _, err := OAuthAuthMethod(AuthMethodTypeDiscord, ""); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("OAuthAuthMethod() without a token error = %v, want ErrInvalidParams", err)
+	}
+}
+
+func TestMintWithAuth_DoesNotMutateParams(t *testing.T) {
+	signer, _ := PrivateKeySignerFromHex(signerTestKey)
+	authSig, _ := NewAuthSig(context.Background(), signer, "hello")
+
+	var bodies []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.Write([]byte(`{"pkp": {"tokenId": "0x1"}}`))
+	}))
+
+	params := MintWithAuthParams{
+		AuthMethod: EthWalletAuthMethod(*authSig),
+		Scopes:     []AuthMethodScope{AuthMethodScopeSignAnything},
+	}
+	saved := params
+	for i := 0; i < 2; i++ {
+		if _, err := client.MintWithAuth(params); err != nil {
+			t.Fatalf("MintWithAuth() call %d error = %v", i+1, err)
+		}
+	}
+	if !reflect.DeepEqual(params, saved) {
+		t.Errorf("Expected the params to be unchanged, got %+v", params)
+	}
+	if len(bodies) != 2 || bodies[0] != bodies[1] {
+		t.Fatalf("Expected two identical requests, got %v", bodies)
+	}
+
+	// The access token is sent as the JSON encoded auth sig
+	var sent struct {
+		AuthMethod struct {
+			AuthMethodType int    `json:"authMethodType"`
+			AccessToken    string `json:"accessToken"`
+		} `json:"authMethod"`
+	}
+	if err := json.Unmarshal([]byte(bodies[0]), &sent); err != nil {
+		t.Fatal(err)
+	}
+	var sentSig AuthSig
+	if err := json.Unmarshal([]byte(sent.AuthMethod.AccessToken), &sentSig); err != nil || sentSig != *authSig || sent.AuthMethod.AuthMethodType != 1 {
+		t.Errorf("Unexpected auth method sent: %s", bodies[0])
+	}
+}
+
+func TestMintWithAuthParams_Validate(t *testing.T) {
+	webAuthn, _ := WebAuthnAuthMethod(json.RawMessage(`{"rawId":"abc"}`))
+	tests := []struct {
+		name    string
+		params  MintWithAuthParams
+		wantErr bool
+	}{
+		{"lit action", MintWithAuthParams{AuthMethod: LitActionAuthMethod("token")}, false},
+		{"webauthn", MintWithAuthParams{AuthMethod: webAuthn, Pubkey: "0x04ab"}, false},
+		{"no auth method", MintWithAuthParams{}, true},
+		{"no access token", MintWithAuthParams{AuthMethod: AuthMethod{AuthMethodType: AuthMethodTypeGoogle}}, true},
+		{"bad auth sig", MintWithAuthParams{AuthMethod: AuthMethod{AuthMethodType: AuthMethodTypeEthWallet, AccessToken: "0xsig"}}, true},
+		{"webauthn without pubkey", MintWithAuthParams{AuthMethod: webAuthn}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("Expected errors.Is(err, ErrInvalidParams), got %v", err)
+			}
+		})
+	}
+}
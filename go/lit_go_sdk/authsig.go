@@ -0,0 +1,136 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// AuthSigDerivedVia is the DerivedVia of auth sigs signed by an Ethereum
+// wallet as a personal message
+const AuthSigDerivedVia = "web3.eth.personal.sign"
+
+// AuthSig is a wallet's signature of a message, usually a SIWE message,
+// proving to the Lit nodes that the signer controls the wallet. It marshals
+// to the JSON the JS SDK uses.
+type AuthSig struct {
+	// Sig is the hex encoded 65 byte signature, with V as 27 or 28
+	Sig string `json:"sig"`
+	// DerivedVia is how the signature was made, AuthSigDerivedVia
+	DerivedVia string `json:"derivedVia"`
+	// SignedMessage is the message that was signed
+	SignedMessage string `json:"signedMessage"`
+	// Address is the EIP-55 checksummed address of the wallet
+	Address string `json:"address"`
+	// Algo is the signature algorithm, if not the wallet's ECDSA
+	Algo string `json:"algo,omitempty"`
+}
+
+// NewAuthSig signs toSign with signer, like generateAuthSig in
+// @lit-protocol/auth-helpers but without a round trip to the server. Use
+// NewPrivateKeySigner to sign with a local key.
+func NewAuthSig(ctx context.Context, signer Signer, toSign string) (*AuthSig, error) {
+	if signer == nil {
+		return nil, ErrNoSigner
+	}
+	sig, err := signer.SignMessage(ctx, []byte(toSign))
+	if err != nil {
+		return nil, fmt.Errorf("lit: signer failed to sign message: %w", err)
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("lit: signer returned a %d byte signature, want %d", len(sig), crypto.SignatureLength)
+	}
+	// ethers encodes V as 27 or 28, some signers return 0 or 1
+	if sig[crypto.RecoveryIDOffset] < 27 {
+		sig = append([]byte(nil), sig...)
+		sig[crypto.RecoveryIDOffset] += 27
+	}
+	return &AuthSig{
+		Sig:           hexutil.Encode(sig),
+		DerivedVia:    AuthSigDerivedVia,
+		SignedMessage: toSign,
+		Address:       signer.Address().Hex(),
+	}, nil
+}
+
+// AuthSigFromResult extracts the auth sig from a server result holding one
+// under authSig
+func AuthSigFromResult(result map[string]interface{}) (*AuthSig, error) {
+	raw, ok := result["authSig"]
+	if !ok || raw == nil {
+		return nil, errors.New("no authSig in result")
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode auth sig: %w", err)
+	}
+	var authSig AuthSig
+	if err := json.Unmarshal(data, &authSig); err != nil {
+		return nil, fmt.Errorf("failed to decode auth sig: %w", err)
+	}
+	return &authSig, nil
+}
+
+// VerifyAuthSig checks that authSig is a valid signature of a SIWE message by
+// the wallet at its address, and that the message is valid now. The error
+// wraps ErrInvalidAuthSig.
+func VerifyAuthSig(authSig AuthSig) error {
+	return authSig.Verify(time.Now())
+}
+
+// Verify is like VerifyAuthSig but checks that the message is valid at now
+func (a AuthSig) Verify(now time.Time) error {
+	if a.DerivedVia != AuthSigDerivedVia {
+		return fmt.Errorf("%w: unsupported derivedVia %q", ErrInvalidAuthSig, a.DerivedVia)
+	}
+	if !common.IsHexAddress(a.Address) {
+		return fmt.Errorf("%w: invalid address %q", ErrInvalidAuthSig, a.Address)
+	}
+	address := common.HexToAddress(a.Address)
+
+	sig, err := hexutil.Decode(a.Sig)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return fmt.Errorf("%w: invalid signature %q", ErrInvalidAuthSig, a.Sig)
+	}
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(a.SignedMessage)), sig)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAuthSig, err)
+	}
+	if signer := crypto.PubkeyToAddress(*pub); signer != address {
+		return fmt.Errorf("%w: signed by %s, not %s", ErrInvalidAuthSig, signer, address)
+	}
+
+	message, err := ParseSiweMessage(a.SignedMessage)
+	if err != nil {
+		return fmt.Errorf("%w: signed message is not a SIWE message: %v", ErrInvalidAuthSig, err)
+	}
+	if err := message.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAuthSig, err)
+	}
+	if common.HexToAddress(message.Address) != address {
+		return fmt.Errorf("%w: SIWE message is for %s, not %s", ErrInvalidAuthSig, message.Address, address)
+	}
+	if message.ExpirationTime != "" {
+		expiration, _ := time.Parse(time.RFC3339Nano, message.ExpirationTime)
+		if !now.Before(expiration) {
+			return fmt.Errorf("%w: SIWE message expired at %s", ErrInvalidAuthSig, message.ExpirationTime)
+		}
+	}
+	if message.NotBefore != "" {
+		notBefore, _ := time.Parse(time.RFC3339Nano, message.NotBefore)
+		if now.Before(notBefore) {
+			return fmt.Errorf("%w: SIWE message not valid before %s", ErrInvalidAuthSig, message.NotBefore)
+		}
+	}
+	return nil
+}
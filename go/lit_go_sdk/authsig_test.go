@@ -0,0 +1,135 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signedSiweMessage returns an auth sig of a SIWE message by the test key,
+// valid until expiration
+func signedSiweMessage(t *testing.T, expiration string) *AuthSig {
+	t.Helper()
+	signer, _ := PrivateKeySignerFromHex(signerTestKey)
+	message, err := NewSiweMessage(SiweMessageParams{
+		URI:           "lit:session:abc",
+		Expiration:    expiration,
+		WalletAddress: signer.Address().Hex(),
+		Nonce:         siweTestNonce,
+		IssuedAt:      "2024-01-01T00:00:00.000Z",
+	})
+	if err != nil {
+		t.Fatalf("NewSiweMessage() error = %v", err)
+	}
+	authSig, err := NewAuthSig(context.Background(), signer, message.String())
+	if err != nil {
+		t.Fatalf("NewAuthSig() error = %v", err)
+	}
+	return authSig
+}
+
+func TestNewAuthSig(t *testing.T) {
+	signer, _ := PrivateKeySignerFromHex(signerTestKey)
+	authSig, err := NewAuthSig(context.Background(), recoveryIDSigner{signer}, "hello")
+	if err != nil {
+		t.Fatalf("NewAuthSig() error = %v", err)
+	}
+
+	// The signature of ethers' Wallet.signMessage("hello") with the test key
+	want := AuthSig{
+		Sig:           "0xf16ea9a3478698f695fd1401bfe27e9e4a7e8e3da94aa72b021125e31fa899cc573c48ea3fe1d4ab61a9db10c19032026e3ed2dbccba5a178235ac27f94504311c",
+		DerivedVia:    "web3.eth.personal.sign",
+		SignedMessage: "hello",
+		Address:       "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+	}
+	if *authSig != want {
+		t.Errorf("NewAuthSig() = %+v, want %+v", *authSig, want)
+	}
+
+	data, _ := json.Marshal(authSig)
+	if !strings.Contains(string(data), `"derivedVia":"web3.eth.personal.sign"`) || strings.Contains(string(data), "algo") {
+		t.Errorf("Expected the JS SDK auth sig JSON, got %s", data)
+	}
+	parsed, err := AuthSigFromResult(map[string]interface{}{"success": true, "authSig": json.RawMessage(data)})
+	if err != nil || *parsed != want {
+		t.Errorf("AuthSigFromResult() = %+v, %v", parsed, err)
+	}
+
+	if _, err := NewAuthSig(context.Background(), nil, "hello"); !errors.Is(err, ErrNoSigner) {
+		t.Errorf("NewAuthSig() without a signer error = %v, want ErrNoSigner", err)
+	}
+}
+
+func TestGenerateAuthSig_NoServerCall(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/setSigner" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"success": true}`))
+	}))
+	if _, err := client.GenerateAuthSig("hello"); !errors.Is(err, ErrNoSigner) {
+		t.Errorf("GenerateAuthSig() without a signer error = %v, want ErrNoSigner", err)
+	}
+
+	signer, _ := PrivateKeySignerFromHex(signerTestKey)
+	if _, err := client.SetSigner(signer); err != nil {
+		t.Fatalf("SetSigner() error = %v", err)
+	}
+	authSig, err := client.GenerateAuthSig("hello")
+	if err != nil {
+		t.Fatalf("GenerateAuthSig() error = %v", err)
+	}
+	want, _ := NewAuthSig(context.Background(), signer, "hello")
+	if *authSig != *want {
+		t.Errorf("GenerateAuthSig() = %+v, want %+v", *authSig, *want)
+	}
+}
+
+func TestVerifyAuthSig(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	valid := signedSiweMessage(t, "2030-01-01T00:00:00.000Z")
+	if err := valid.Verify(now); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if err := VerifyAuthSig(*valid); err != nil {
+		t.Errorf("VerifyAuthSig() error = %v", err)
+	}
+
+	// A lowercase address is the same wallet
+	lowercase := *valid
+	lowercase.Address = strings.ToLower(valid.Address)
+	if err := lowercase.Verify(now); err != nil {
+		t.Errorf("Verify() with a lowercase address error = %v", err)
+	}
+
+	other, _ := PrivateKeySignerFromHex("0x" + strings.Repeat("11", 32))
+	signedByOther, _ := NewAuthSig(context.Background(), other, valid.SignedMessage)
+	notSIWE, _ := NewAuthSig(context.Background(), other, "hello")
+
+	tests := []struct {
+		name    string
+		authSig AuthSig
+		now     time.Time
+	}{
+		{"expired", *signedSiweMessage(t, "2024-06-01T00:00:00.000Z"), now},
+		{"at expiration", *valid, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"wrong address", AuthSig{Sig: valid.Sig, DerivedVia: valid.DerivedVia, SignedMessage: valid.SignedMessage, Address: other.Address().Hex()}, now},
+		{"message for another wallet", *signedByOther, now},
+		{"tampered message", AuthSig{Sig: valid.Sig, DerivedVia: valid.DerivedVia, SignedMessage: strings.Replace(valid.SignedMessage, "2030", "2040", 1), Address: valid.Address}, now},
+		{"not a SIWE message", *notSIWE, now},
+		{"bad signature", AuthSig{Sig: "0x1234", DerivedVia: valid.DerivedVia, SignedMessage: valid.SignedMessage, Address: valid.Address}, now},
+		{"bad address", AuthSig{Sig: valid.Sig, DerivedVia: valid.DerivedVia, SignedMessage: valid.SignedMessage, Address: "alice"}, now},
+		{"session sig", AuthSig{Sig: valid.Sig, DerivedVia: "litSessionSignViaNacl", SignedMessage: valid.SignedMessage, Address: valid.Address}, now},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.authSig.Verify(tt.now); !errors.Is(err, ErrInvalidAuthSig) {
+				t.Errorf("Verify() error = %v, want ErrInvalidAuthSig", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package lit_go_sdk
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// bundledServerFile is the name of the JS SDK server bundle, in the embedded
+// bundle directory and in the cache directory it is extracted to
+const bundledServerFile = "bundled_server.js"
+
+// bundleFS holds the JS SDK server bundle built by `npm run build` in js-sdk-server
+//
+//go:embed bundle
+var bundleFS embed.FS
+
+// bundledServerScript returns the embedded JS SDK server bundle
+func bundledServerScript() ([]byte, error) {
+	script, err := bundleFS.ReadFile("bundle/" + bundledServerFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("bundled server was not embedded in this build: run `npm run build` in js-sdk-server and rebuild")
+	}
+	return script, err
+}
+
+// extractBundledServer writes the embedded JS SDK server bundle to a cache
+// directory and returns its path. If cacheDir is empty the user cache
+// directory is used, falling back to the system temp directory when that is
+// not writable.
+func extractBundledServer(cacheDir string) (string, error) {
+	script, err := bundledServerScript()
+	if err != nil {
+		return "", err
+	}
+
+	if cacheDir != "" {
+		return extractScript(script, cacheDir)
+	}
+
+	var dirs []string
+	if userCacheDir, err := os.UserCacheDir(); err == nil {
+		dirs = append(dirs, filepath.Join(userCacheDir, "lit-go-sdk"))
+	}
+	dirs = append(dirs, filepath.Join(os.TempDir(), fmt.Sprintf("lit-go-sdk-%d", os.Getuid())))
+
+	var errs []error
+	for _, dir := range dirs {
+		path, err := extractScript(script, dir)
+		if err == nil {
+			return path, nil
+		}
+		errs = append(errs, err)
+	}
+	return "", errors.Join(errs...)
+}
+
+// extractScript writes script into a subdirectory of cacheDir named after its
+// SHA-256 digest. An existing copy is reused if its contents still match the
+// digest, otherwise it is replaced.
+func extractScript(script []byte, cacheDir string) (string, error) {
+	sum := sha256.Sum256(script)
+	dir := filepath.Join(cacheDir, hex.EncodeToString(sum[:]))
+	path := filepath.Join(dir, bundledServerFile)
+
+	if verifyScript(path, sum) == nil {
+		return path, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create bundled server cache directory: %w", err)
+	}
+
+	// Write to a temporary file and rename it into place so a concurrent
+	// reader never sees a partially written script
+	tmp, err := os.CreateTemp(dir, bundledServerFile+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to extract bundled server: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(script); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to extract bundled server: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to extract bundled server: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to extract bundled server: %w", err)
+	}
+
+	if err := verifyScript(path, sum); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// verifyScript checks that the file at path has the given SHA-256 digest
+func verifyScript(path string, want [sha256.Size]byte) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if !bytes.Equal(h.Sum(nil), want[:]) {
+		return fmt.Errorf("bundled server at %s failed its integrity check", path)
+	}
+	return nil
+}
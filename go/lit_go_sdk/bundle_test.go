@@ -0,0 +1,87 @@
+package lit_go_sdk
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractScript(t *testing.T) {
+	cacheDir := t.TempDir()
+	script := []byte("console.log('hello from the bundle');\n")
+
+	path, err := extractScript(script, cacheDir)
+	if err != nil {
+		t.Fatalf("extractScript() error = %v", err)
+	}
+	if filepath.Base(path) != bundledServerFile {
+		t.Errorf("Expected script to be named %s, got %s", bundledServerFile, path)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read extracted script: %v", err)
+	}
+	if !bytes.Equal(got, script) {
+		t.Errorf("Extracted script = %q, want %q", got, script)
+	}
+
+	// Extracting the same script again reuses the cached copy
+	again, err := extractScript(script, cacheDir)
+	if err != nil {
+		t.Fatalf("extractScript() error = %v", err)
+	}
+	if again != path {
+		t.Errorf("Expected cached path %s, got %s", path, again)
+	}
+
+	// A different script is stored under its own digest
+	other, err := extractScript([]byte("console.log('v2');\n"), cacheDir)
+	if err != nil {
+		t.Fatalf("extractScript() error = %v", err)
+	}
+	if filepath.Dir(other) == filepath.Dir(path) {
+		t.Error("Expected different scripts to be stored in different directories")
+	}
+}
+
+func TestExtractScript_ReplacesTamperedCopy(t *testing.T) {
+	cacheDir := t.TempDir()
+	script := []byte("console.log('original');\n")
+
+	path, err := extractScript(script, cacheDir)
+	if err != nil {
+		t.Fatalf("extractScript() error = %v", err)
+	}
+	if err := os.WriteFile(path, []byte("console.log('tampered');\n"), 0o600); err != nil {
+		t.Fatalf("Failed to tamper with script: %v", err)
+	}
+
+	path, err = extractScript(script, cacheDir)
+	if err != nil {
+		t.Fatalf("extractScript() error = %v", err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read extracted script: %v", err)
+	}
+	if !bytes.Equal(got, script) {
+		t.Errorf("Expected tampered script to be replaced, got %q", got)
+	}
+}
+
+func TestBundledServerScript(t *testing.T) {
+	script, err := bundledServerScript()
+	if err != nil {
+		// publish.sh sets LIT_GO_SDK_REQUIRE_BUNDLE so a release never ships
+		// without the bundle
+		if os.Getenv("LIT_GO_SDK_REQUIRE_BUNDLE") != "" {
+			t.Fatalf("bundledServerScript() error = %v", err)
+		}
+		t.Skipf("Skipping: %v", err)
+	}
+	if len(script) == 0 {
+		t.Error("Expected a non-empty bundled server")
+	}
+}
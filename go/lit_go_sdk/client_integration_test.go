@@ -1,21 +1,36 @@
+//go:build integration
+
 package lit_go_sdk
 
 import (
-	"crypto/ecdsa"
 	"encoding/hex"
+	"errors"
 	"os"
 	"strings"
 	"testing"
 	"time"
 
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/joho/godotenv"
 )
 
 var (
 	integrationClient *LitNodeClient
+	integrationSigner TransactionSigner
 )
 
+// integrationSignerFromEnv returns a signer for the keystore file in
+// LIT_POLYGLOT_SDK_TEST_KEYSTORE, or else for LIT_POLYGLOT_SDK_TEST_PRIVATE_KEY
+func integrationSignerFromEnv() (TransactionSigner, error) {
+	if path := os.Getenv("LIT_POLYGLOT_SDK_TEST_KEYSTORE"); path != "" {
+		return KeystoreSignerFromFile(path, os.Getenv("LIT_POLYGLOT_SDK_TEST_KEYSTORE_PASSPHRASE"))
+	}
+	privateKey := os.Getenv("LIT_POLYGLOT_SDK_TEST_PRIVATE_KEY")
+	if privateKey == "" {
+		return nil, errors.New("LIT_POLYGLOT_SDK_TEST_KEYSTORE or LIT_POLYGLOT_SDK_TEST_PRIVATE_KEY environment variable is required")
+	}
+	return PrivateKeySignerFromHex(privateKey)
+}
+
 func TestMain(m *testing.M) {
 	// Load .env file from root directory
 	godotenv.Load("../../.env")
@@ -27,13 +42,13 @@ func TestMain(m *testing.M) {
 		panic(err)
 	}
 
-	// Set auth token from environment
-	authToken := os.Getenv("LIT_POLYGLOT_SDK_TEST_PRIVATE_KEY")
-	if authToken == "" {
-		panic("LIT_POLYGLOT_SDK_TEST_PRIVATE_KEY environment variable is required")
+	// Set the signer from the keystore file or private key in the environment
+	integrationSigner, err = integrationSignerFromEnv()
+	if err != nil {
+		panic(err)
 	}
 
-	_, err = integrationClient.SetAuthToken(authToken)
+	_, err = integrationClient.SetSigner(integrationSigner)
 	if err != nil {
 		panic(err)
 	}
@@ -50,7 +65,7 @@ func TestMain(m *testing.M) {
 func TestIntegration_BasicFlow(t *testing.T) {
 	// Test New
 	_, err := integrationClient.New(LitNodeClientConfig{
-		LitNetwork: "datil-test",
+		LitNetwork: LitNetworkDatilTest,
 		Debug:      true,
 	})
 	if err != nil {
@@ -101,28 +116,17 @@ func TestIntegration_BasicFlow(t *testing.T) {
 
 func TestIntegration_ExecuteJs(t *testing.T) {
 	// First get session sigs
-	sessionSigsResult, err := integrationClient.GetSessionSigs(SessionSigsParams{
-		Chain:      "ethereum",
+	sessionSigs, err := integrationClient.GetSessionSigs(SessionSigsParams{
+		Chain:      ChainEthereum,
 		Expiration: time.Now().Add(10 * time.Minute).Format(time.RFC3339),
-		ResourceAbilityRequests: []interface{}{
-			map[string]interface{}{
-				"resource": map[string]interface{}{
-					"resource":       "*",
-					"resourcePrefix": "lit-litaction",
-				},
-				"ability": "lit-action-execution",
-			},
+		ResourceAbilityRequests: []ResourceAbilityRequest{
+			LitActionResource("*").Request(LitAbilityLitActionExecution),
 		},
 	})
 	if err != nil {
 		t.Fatalf("GetSessionSigs() error = %v", err)
 	}
 
-	sessionSigs, ok := sessionSigsResult["sessionSigs"].(map[string]interface{})
-	if !ok {
-		t.Fatal("Expected sessionSigs in response")
-	}
-
 	// Now execute JS
 	result, err := integrationClient.ExecuteJs(ExecuteJsParams{
 		Code: `
@@ -138,60 +142,33 @@ func TestIntegration_ExecuteJs(t *testing.T) {
 		t.Fatalf("ExecuteJs() error = %v", err)
 	}
 
-	response, ok := result["response"].(string)
-	if !ok || response != "Test successful" {
-		t.Errorf("Expected response to be 'Test successful', got %v", result["response"])
+	if response := result.Response.String(); response != "Test successful" {
+		t.Errorf("Expected response to be 'Test successful', got %v", response)
+	}
+	if !strings.Contains(result.Logs, "Testing executeJs endpoint") {
+		t.Errorf("Expected the action's log line in Logs, got %q", result.Logs)
 	}
 }
 
 func TestIntegration_ContractsAndAuth(t *testing.T) {
 	// Test NewLitContractsClient
 	_, err := integrationClient.NewLitContractsClient(LitContractsClientConfig{
-		PrivateKey: os.Getenv("LIT_POLYGLOT_SDK_TEST_PRIVATE_KEY"),
-		Network:    "datil-test",
-		Debug:      true,
+		Network: LitNetworkDatilTest,
+		Debug:   true,
 	})
 	if err != nil {
 		t.Fatalf("NewLitContractsClient() error = %v", err)
 	}
 
-	// Derive wallet address from private key
-	privateKeyHex := os.Getenv("LIT_POLYGLOT_SDK_TEST_PRIVATE_KEY")
-	if privateKeyHex == "" {
-		t.Fatal("LIT_POLYGLOT_SDK_TEST_PRIVATE_KEY environment variable not set")
-	}
-
-	privateKeyBytes, err := hex.DecodeString(strings.TrimPrefix(privateKeyHex, "0x"))
-	if err != nil {
-		t.Fatalf("Failed to decode private key: %v", err)
-	}
-
-	privateKey, err := crypto.ToECDSA(privateKeyBytes)
-	if err != nil {
-		t.Fatalf("Failed to convert private key: %v", err)
-	}
-
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		t.Fatal("Failed to get public key")
-	}
-
-	address := crypto.PubkeyToAddress(*publicKeyECDSA).Hex()
+	address := integrationSigner.Address().Hex()
 	t.Logf("Using wallet address: %s", address)
 
 	// Create SIWE message
 	siweResult, err := integrationClient.CreateSiweMessage(CreateSiweMessageParams{
 		URI:        "http://localhost:3092",
 		Expiration: time.Now().Add(10 * time.Minute).Format(time.RFC3339),
-		Resources: []interface{}{
-			map[string]interface{}{
-				"resource": map[string]interface{}{
-					"resource":       "*",
-					"resourcePrefix": "lit-litaction",
-				},
-				"ability": "lit-action-execution",
-			},
+		Resources: []ResourceAbilityRequest{
+			LitActionResource("*").Request(LitAbilityLitActionExecution),
 		},
 		WalletAddress: address,
 	})
@@ -205,23 +182,18 @@ func TestIntegration_ContractsAndAuth(t *testing.T) {
 	}
 
 	// Generate auth sig
-	authSigResult, err := integrationClient.GenerateAuthSig(siweMessage)
+	authSig, err := integrationClient.GenerateAuthSig(siweMessage)
 	if err != nil {
 		t.Fatalf("GenerateAuthSig() error = %v", err)
 	}
-
-	authSig, ok := authSigResult["authSig"].(map[string]interface{})
-	if !ok {
-		t.Fatal("Expected authSig in response")
+	if err := VerifyAuthSig(*authSig); err != nil {
+		t.Errorf("VerifyAuthSig() error = %v", err)
 	}
 
 	// Test MintWithAuth
 	mintResult, err := integrationClient.MintWithAuth(MintWithAuthParams{
-		AuthMethod: map[string]interface{}{
-			"authMethodType": 1, // EthWallet
-			"accessToken":    authSig,
-		},
-		Scopes: []int{1},
+		AuthMethod: EthWalletAuthMethod(*authSig),
+		Scopes:     []AuthMethodScope{AuthMethodScopeSignAnything},
 	})
 	if err != nil {
 		t.Fatalf("MintWithAuth() error = %v", err)
@@ -233,35 +205,18 @@ func TestIntegration_ContractsAndAuth(t *testing.T) {
 	}
 
 	// Test PKPSign
-	sessionSigsResult, err := integrationClient.GetSessionSigs(SessionSigsParams{
-		Chain:      "ethereum",
+	sessionSigs, err := integrationClient.GetSessionSigs(SessionSigsParams{
+		Chain:      ChainEthereum,
 		Expiration: time.Now().Add(10 * time.Minute).Format(time.RFC3339),
-		ResourceAbilityRequests: []interface{}{
-			map[string]interface{}{
-				"resource": map[string]interface{}{
-					"resource":       "*",
-					"resourcePrefix": "lit-pkp",
-				},
-				"ability": "pkp-signing",
-			},
-			map[string]interface{}{
-				"resource": map[string]interface{}{
-					"resource":       "*",
-					"resourcePrefix": "lit-litaction",
-				},
-				"ability": "lit-action-execution",
-			},
+		ResourceAbilityRequests: []ResourceAbilityRequest{
+			LitPKPResource("*").Request(LitAbilityPKPSigning),
+			LitActionResource("*").Request(LitAbilityLitActionExecution),
 		},
 	})
 	if err != nil {
 		t.Fatalf("GetSessionSigs() error = %v", err)
 	}
 
-	sessionSigs, ok := sessionSigsResult["sessionSigs"].(map[string]interface{})
-	if !ok {
-		t.Fatal("Expected sessionSigs in response")
-	}
-
 	toSignHex := "0xadb20420bde8cda6771249188817098fca8ccf8eef2120a31e3f64f5812026bf" // Example hex string
 	hexStr := strings.TrimPrefix(toSignHex, "0x")
 	bytes, _ := hex.DecodeString(hexStr)
@@ -293,76 +248,28 @@ func TestIntegration_GetLogs(t *testing.T) {
 }
 
 func TestIntegration_EncryptAndDecryptString(t *testing.T) {
-	// Derive wallet address from private key
-	privateKeyHex := os.Getenv("LIT_POLYGLOT_SDK_TEST_PRIVATE_KEY")
-	if privateKeyHex == "" {
-		t.Fatal("LIT_POLYGLOT_SDK_TEST_PRIVATE_KEY environment variable not set")
-	}
-
-	privateKeyBytes, err := hex.DecodeString(strings.TrimPrefix(privateKeyHex, "0x"))
-	if err != nil {
-		t.Fatalf("Failed to decode private key: %v", err)
-	}
-
-	privateKey, err := crypto.ToECDSA(privateKeyBytes)
-	if err != nil {
-		t.Fatalf("Failed to convert private key: %v", err)
-	}
-
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		t.Fatal("Failed to get public key")
-	}
-
-	address := crypto.PubkeyToAddress(*publicKeyECDSA).Hex()
+	address := integrationSigner.Address().Hex()
 
 	// Get session signatures
-	sessionSigsResult, err := integrationClient.GetSessionSigs(SessionSigsParams{
-		Chain:      "ethereum",
+	sessionSigs, err := integrationClient.GetSessionSigs(SessionSigsParams{
+		Chain:      ChainEthereum,
 		Expiration: time.Now().Add(10 * time.Minute).Format(time.RFC3339),
-		ResourceAbilityRequests: []interface{}{
-			map[string]interface{}{
-				"resource": map[string]interface{}{
-					"resource":       "*",
-					"resourcePrefix": "lit-litaction",
-				},
-				"ability": "lit-action-execution",
-			},
-			map[string]interface{}{
-				"resource": map[string]interface{}{
-					"resource":       "*",
-					"resourcePrefix": "lit-pkp",
-				},
-				"ability": "pkp-signing",
-			},
+		ResourceAbilityRequests: []ResourceAbilityRequest{
+			LitActionResource("*").Request(LitAbilityLitActionExecution),
+			LitPKPResource("*").Request(LitAbilityPKPSigning),
 		},
 	})
 	if err != nil {
 		t.Fatalf("GetSessionSigs() error = %v", err)
 	}
 
-	sessionSigs := sessionSigsResult["sessionSigs"].(map[string]interface{})
-
 	// Test string to encrypt
 	testString := "Hello, World!"
 
 	// Test encryption
 	encryptResult, err := integrationClient.EncryptString(EncryptStringParams{
-		DataToEncrypt: testString,
-		AccessControlConditions: []interface{}{
-			map[string]interface{}{
-				"contractAddress":      "",
-				"standardContractType": "",
-				"chain":                "ethereum",
-				"method":               "",
-				"parameters":           []string{":userAddress"},
-				"returnValueTest": map[string]interface{}{
-					"comparator": "=",
-					"value":      address,
-				},
-			},
-		},
+		DataToEncrypt:           testString,
+		AccessControlConditions: Conditions{WalletAddressCondition(ChainEthereum, address)},
 	})
 	if err != nil {
 		t.Fatalf("EncryptString() error = %v", err)
@@ -380,23 +287,11 @@ func TestIntegration_EncryptAndDecryptString(t *testing.T) {
 
 	// Test decryption
 	decryptResult, err := integrationClient.DecryptString(DecryptStringParams{
-		Chain:             "ethereum",
-		Ciphertext:        ciphertext,
-		DataToEncryptHash: dataToEncryptHash,
-		AccessControlConditions: []interface{}{
-			map[string]interface{}{
-				"contractAddress":      "",
-				"standardContractType": "",
-				"chain":                "ethereum",
-				"method":               "",
-				"parameters":           []string{":userAddress"},
-				"returnValueTest": map[string]interface{}{
-					"comparator": "=",
-					"value":      address,
-				},
-			},
-		},
-		SessionSigs: sessionSigs,
+		Chain:                   ChainEthereum,
+		Ciphertext:              ciphertext,
+		DataToEncryptHash:       dataToEncryptHash,
+		AccessControlConditions: Conditions{WalletAddressCondition(ChainEthereum, address)},
+		SessionSigs:             sessionSigs,
 	})
 	if err != nil {
 		t.Fatalf("DecryptString() error = %v", err)
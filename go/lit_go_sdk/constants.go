@@ -0,0 +1,288 @@
+package lit_go_sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// The constants in this file mirror @lit-protocol/constants. The JS values
+// are dumped by `npm run constants` in js-sdk-server and compared to these in
+// TestConstantsParity.
+
+// LitNetwork is a Lit network, LIT_NETWORK in the JS SDK
+type LitNetwork string
+
+const (
+	// LitNetworkDatilDev is the development network, free to use
+	LitNetworkDatilDev LitNetwork = "datil-dev"
+	// LitNetworkDatilTest is the test network, which needs capacity credits
+	LitNetworkDatilTest LitNetwork = "datil-test"
+	// LitNetworkDatil is the production network
+	LitNetworkDatil LitNetwork = "datil"
+	// LitNetworkCustom is a locally run network
+	LitNetworkCustom LitNetwork = "custom"
+)
+
+// litNetworks are the known Lit networks
+var litNetworks = []LitNetwork{LitNetworkDatilDev, LitNetworkDatilTest, LitNetworkDatil, LitNetworkCustom}
+
+// String returns the name of the network
+func (n LitNetwork) String() string {
+	return string(n)
+}
+
+// ParseLitNetwork returns the Lit network named s
+func ParseLitNetwork(s string) (LitNetwork, error) {
+	return parseStringEnum("Lit network", s, litNetworks)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, rejecting unknown networks
+func (n *LitNetwork) UnmarshalJSON(data []byte) error {
+	return unmarshalStringEnum(data, n, ParseLitNetwork)
+}
+
+// AuthMethodType is a kind of auth method, AUTH_METHOD_TYPE in the JS SDK.
+// It is encoded in JSON as a number.
+type AuthMethodType int
+
+const (
+	// AuthMethodTypeEthWallet is an auth sig from an Ethereum wallet
+	AuthMethodTypeEthWallet AuthMethodType = 1
+	// AuthMethodTypeLitAction is a Lit Action
+	AuthMethodTypeLitAction AuthMethodType = 2
+	// AuthMethodTypeWebAuthn is a WebAuthn credential
+	AuthMethodTypeWebAuthn AuthMethodType = 3
+	// AuthMethodTypeDiscord is a Discord OAuth token
+	AuthMethodTypeDiscord AuthMethodType = 4
+	// AuthMethodTypeGoogle is a Google OAuth token
+	AuthMethodTypeGoogle AuthMethodType = 5
+	// AuthMethodTypeGoogleJwt is a Google OIDC JWT
+	AuthMethodTypeGoogleJwt AuthMethodType = 6
+	// AuthMethodTypeAppleJwt is an Apple OIDC JWT
+	AuthMethodTypeAppleJwt AuthMethodType = 8
+	// AuthMethodTypeStytchOtp is a Stytch OTP session token
+	AuthMethodTypeStytchOtp AuthMethodType = 9
+	// AuthMethodTypeStytchEmailFactorOtp is a Stytch email OTP session token
+	AuthMethodTypeStytchEmailFactorOtp AuthMethodType = 10
+	// AuthMethodTypeStytchSmsFactorOtp is a Stytch SMS OTP session token
+	AuthMethodTypeStytchSmsFactorOtp AuthMethodType = 11
+	// AuthMethodTypeStytchWhatsAppFactorOtp is a Stytch WhatsApp OTP session token
+	AuthMethodTypeStytchWhatsAppFactorOtp AuthMethodType = 12
+	// AuthMethodTypeStytchTotpFactorOtp is a Stytch TOTP session token
+	AuthMethodTypeStytchTotpFactorOtp AuthMethodType = 13
+)
+
+// authMethodTypeNames are the JS names of the known auth method types
+var authMethodTypeNames = map[AuthMethodType]string{
+	AuthMethodTypeEthWallet:               "EthWallet",
+	AuthMethodTypeLitAction:               "LitAction",
+	AuthMethodTypeWebAuthn:                "WebAuthn",
+	AuthMethodTypeDiscord:                 "Discord",
+	AuthMethodTypeGoogle:                  "Google",
+	AuthMethodTypeGoogleJwt:               "GoogleJwt",
+	AuthMethodTypeAppleJwt:                "AppleJwt",
+	AuthMethodTypeStytchOtp:               "StytchOtp",
+	AuthMethodTypeStytchEmailFactorOtp:    "StytchEmailFactorOtp",
+	AuthMethodTypeStytchSmsFactorOtp:      "StytchSmsFactorOtp",
+	AuthMethodTypeStytchWhatsAppFactorOtp: "StytchWhatsAppFactorOtp",
+	AuthMethodTypeStytchTotpFactorOtp:     "StytchTotpFactorOtp",
+}
+
+// String returns the JS name of the auth method type, e.g. EthWallet
+func (t AuthMethodType) String() string {
+	return enumName(int(t), authMethodTypeNames[t], "AuthMethodType")
+}
+
+// ParseAuthMethodType returns the auth method type with the JS name or number s
+func ParseAuthMethodType(s string) (AuthMethodType, error) {
+	return parseIntEnum("auth method type", s, authMethodTypeNames)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts any number, so auth
+// methods added to the network later can be decoded, or the JS name of a known
+// auth method type.
+func (t *AuthMethodType) UnmarshalJSON(data []byte) error {
+	return unmarshalIntEnum(data, t, ParseAuthMethodType)
+}
+
+// AuthMethodScope is what a PKP allows an auth method to do,
+// AUTH_METHOD_SCOPE in the JS SDK. It is encoded in JSON as a number.
+type AuthMethodScope int
+
+const (
+	// AuthMethodScopeNoPermissions allows nothing
+	AuthMethodScopeNoPermissions AuthMethodScope = 0
+	// AuthMethodScopeSignAnything allows signing any data with the PKP
+	AuthMethodScopeSignAnything AuthMethodScope = 1
+	// AuthMethodScopePersonalSign allows signing EIP-191 messages with the PKP
+	AuthMethodScopePersonalSign AuthMethodScope = 2
+)
+
+// authMethodScopeNames are the JS names of the known auth method scopes
+var authMethodScopeNames = map[AuthMethodScope]string{
+	AuthMethodScopeNoPermissions: "NoPermissions",
+	AuthMethodScopeSignAnything:  "SignAnything",
+	AuthMethodScopePersonalSign:  "PersonalSign",
+}
+
+// String returns the JS name of the scope, e.g. SignAnything
+func (s AuthMethodScope) String() string {
+	return enumName(int(s), authMethodScopeNames[s], "AuthMethodScope")
+}
+
+// ParseAuthMethodScope returns the auth method scope with the JS name or number s
+func ParseAuthMethodScope(s string) (AuthMethodScope, error) {
+	return parseIntEnum("auth method scope", s, authMethodScopeNames)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts any number or the JS
+// name of a known auth method scope.
+func (s *AuthMethodScope) UnmarshalJSON(data []byte) error {
+	return unmarshalIntEnum(data, s, ParseAuthMethodScope)
+}
+
+// Chain is the name of a chain access control conditions and auth sigs can
+// refer to, a key of LIT_CHAINS in the JS SDK. Only the most common chains
+// have constants, any other LIT_CHAINS name can be converted to a Chain.
+type Chain string
+
+const (
+	ChainEthereum    Chain = "ethereum"
+	ChainSepolia     Chain = "sepolia"
+	ChainPolygon     Chain = "polygon"
+	ChainAmoy        Chain = "amoy"
+	ChainArbitrum    Chain = "arbitrum"
+	ChainOptimism    Chain = "optimism"
+	ChainBase        Chain = "base"
+	ChainBaseSepolia Chain = "baseSepolia"
+	ChainAvalanche   Chain = "avalanche"
+	ChainBsc         Chain = "bsc"
+	ChainFantom      Chain = "fantom"
+	ChainXdai        Chain = "xdai"
+	ChainZksync      Chain = "zksync"
+	ChainYellowstone Chain = "yellowstone"
+)
+
+// chains are the chains with constants
+var chains = []Chain{
+	ChainEthereum, ChainSepolia, ChainPolygon, ChainAmoy, ChainArbitrum, ChainOptimism, ChainBase,
+	ChainBaseSepolia, ChainAvalanche, ChainBsc, ChainFantom, ChainXdai, ChainZksync, ChainYellowstone,
+}
+
+// String returns the name of the chain
+func (c Chain) String() string {
+	return string(c)
+}
+
+// ParseChain returns the chain named s, which must be one of the Chain constants
+func ParseChain(s string) (Chain, error) {
+	return parseStringEnum("chain", s, chains)
+}
+
+// String returns the name of the ability
+func (a LitAbility) String() string {
+	return string(a)
+}
+
+// ParseLitAbility returns the ability named s
+func ParseLitAbility(s string) (LitAbility, error) {
+	return parseStringEnum("Lit ability", s, litAbilities)
+}
+
+// String returns the resource prefix
+func (p LitResourcePrefix) String() string {
+	return string(p)
+}
+
+// ParseLitResourcePrefix returns the resource prefix s
+func ParseLitResourcePrefix(s string) (LitResourcePrefix, error) {
+	return parseStringEnum("Lit resource prefix", s, litResourcePrefixes)
+}
+
+// litAbilities are the known abilities
+var litAbilities = []LitAbility{
+	LitAbilityAccessControlConditionDecryption,
+	LitAbilityAccessControlConditionSigning,
+	LitAbilityPKPSigning,
+	LitAbilityRateLimitIncreaseAuth,
+	LitAbilityLitActionExecution,
+}
+
+// litResourcePrefixes are the known resource prefixes
+var litResourcePrefixes = []LitResourcePrefix{
+	LitResourcePrefixAccessControlCondition,
+	LitResourcePrefixPKP,
+	LitResourcePrefixRLI,
+	LitResourcePrefixLitAction,
+}
+
+// parseStringEnum returns s as a T if it is one of values
+func parseStringEnum[T ~string](kind, s string, values []T) (T, error) {
+	for _, value := range values {
+		if string(value) == s {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("%w: unknown %s %q", ErrInvalidParams, kind, s)
+}
+
+// unmarshalStringEnum decodes a JSON string into v with parse
+func unmarshalStringEnum[T ~string](data []byte, v *T, parse func(string) (T, error)) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := parse(s)
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+// parseIntEnum returns the value of names with the name or number s
+func parseIntEnum[T ~int](kind, s string, names map[T]string) (T, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if _, ok := names[T(n)]; ok {
+			return T(n), nil
+		}
+	}
+	for value, name := range names {
+		if name == s {
+			return value, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unknown %s %q", ErrInvalidParams, kind, s)
+}
+
+// unmarshalIntEnum decodes a JSON number into v, or a JSON string with parse
+func unmarshalIntEnum[T ~int](data []byte, v *T, parse func(string) (T, error)) error {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		var n int
+		if err := json.Unmarshal(data, &n); err != nil {
+			return err
+		}
+		*v = T(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := parse(s)
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+// enumName returns name, or typeName(n) for values without one
+func enumName(n int, name, typeName string) string {
+	if name == "" {
+		return fmt.Sprintf("%s(%d)", typeName, n)
+	}
+	return name
+}
@@ -0,0 +1,197 @@
+package lit_go_sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// jsConstants are the @lit-protocol/constants values dumped by
+// `npm run constants` in js-sdk-server
+type jsConstants struct {
+	Version           string            `json:"version"`
+	LitNetwork        map[string]string `json:"LIT_NETWORK"`
+	AuthMethodType    map[string]int    `json:"AUTH_METHOD_TYPE"`
+	AuthMethodScope   map[string]int    `json:"AUTH_METHOD_SCOPE"`
+	LitAbility        map[string]string `json:"LIT_ABILITY"`
+	LitResourcePrefix map[string]string `json:"LIT_RESOURCE_PREFIX"`
+	LitChains         []string          `json:"LIT_CHAINS"`
+}
+
+// requireParityEnv makes the parity tests fail instead of skipping when the
+// files dumped from the JS SDK are missing. CI sets it after dumping them.
+const requireParityEnv = "LIT_GO_SDK_REQUIRE_PARITY"
+
+// readParityFile reads a file dumped from the JS SDK by the npm script
+// script in js-sdk-server
+func readParityFile(t *testing.T, name, script string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if errors.Is(err, os.ErrNotExist) {
+		message := fmt.Sprintf("testdata/%s not found, so nothing is compared: run `npm run %s` in js-sdk-server", name, script)
+		if os.Getenv(requireParityEnv) != "" {
+			t.Fatal(message)
+		}
+		t.Skip(message)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestConstantsParity(t *testing.T) {
+	data := readParityFile(t, "lit_constants.json", "constants")
+	var js jsConstants
+	if err := json.Unmarshal(data, &js); err != nil {
+		t.Fatalf("failed to parse lit_constants.json: %v", err)
+	}
+	t.Logf("Checking against @lit-protocol/constants %s", js.Version)
+
+	compareSets(t, "LIT_NETWORK", stringValues(js.LitNetwork), stringsOf(litNetworks))
+	compareSets(t, "LIT_ABILITY", stringValues(js.LitAbility), stringsOf(litAbilities))
+	compareSets(t, "LIT_RESOURCE_PREFIX", stringValues(js.LitResourcePrefix), stringsOf(litResourcePrefixes))
+
+	goTypes := make(map[string]int)
+	for value, name := range authMethodTypeNames {
+		goTypes[name] = int(value)
+	}
+	compareNumbered(t, "AUTH_METHOD_TYPE", js.AuthMethodType, goTypes)
+	goScopes := make(map[string]int)
+	for value, name := range authMethodScopeNames {
+		goScopes[name] = int(value)
+	}
+	compareNumbered(t, "AUTH_METHOD_SCOPE", js.AuthMethodScope, goScopes)
+
+	// Only the common chains have constants
+	jsChains := make(map[string]bool)
+	for _, chain := range js.LitChains {
+		jsChains[chain] = true
+	}
+	for _, chain := range chains {
+		if !jsChains[string(chain)] {
+			t.Errorf("Chain %q is not in LIT_CHAINS", chain)
+		}
+	}
+}
+
+// compareSets reports the values only one of js and goValues has
+func compareSets(t *testing.T, name string, js, goValues []string) {
+	t.Helper()
+	sort.Strings(js)
+	sort.Strings(goValues)
+	if !equalStrings(js, goValues) {
+		t.Errorf("%s = %v in JS, %v in Go", name, js, goValues)
+	}
+}
+
+// compareNumbered reports the names whose numbers differ between js and goValues
+func compareNumbered(t *testing.T, name string, js, goValues map[string]int) {
+	t.Helper()
+	for key, value := range js {
+		if goValue, ok := goValues[key]; !ok || goValue != value {
+			t.Errorf("%s.%s = %d in JS, missing or different in Go", name, key, value)
+		}
+	}
+	for key := range goValues {
+		if _, ok := js[key]; !ok {
+			t.Errorf("%s.%s is in Go but not in JS", name, key)
+		}
+	}
+}
+
+func stringValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, value := range m {
+		values = append(values, value)
+	}
+	return values
+}
+
+func stringsOf[T ~string](values []T) []string {
+	out := make([]string, len(values))
+	for i, value := range values {
+		out[i] = string(value)
+	}
+	return out
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParseConstants(t *testing.T) {
+	if network, err := ParseLitNetwork("datil-test"); err != nil || network != LitNetworkDatilTest {
+		t.Errorf("ParseLitNetwork() = %v, %v", network, err)
+	}
+	if _, err := ParseLitNetwork("cayenne"); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("ParseLitNetwork(cayenne) error = %v, want ErrInvalidParams", err)
+	}
+
+	for _, s := range []string{"EthWallet", "1"} {
+		if authMethodType, err := ParseAuthMethodType(s); err != nil || authMethodType != AuthMethodTypeEthWallet {
+			t.Errorf("ParseAuthMethodType(%s) = %v, %v", s, authMethodType, err)
+		}
+	}
+	if _, err := ParseAuthMethodType("7"); err == nil {
+		t.Error("Expected an error for an unknown auth method type")
+	}
+	if scope, err := ParseAuthMethodScope("PersonalSign"); err != nil || scope != AuthMethodScopePersonalSign {
+		t.Errorf("ParseAuthMethodScope() = %v, %v", scope, err)
+	}
+	if ability, err := ParseLitAbility("pkp-signing"); err != nil || ability != LitAbilityPKPSigning {
+		t.Errorf("ParseLitAbility() = %v, %v", ability, err)
+	}
+	if chain, err := ParseChain("baseSepolia"); err != nil || chain != ChainBaseSepolia {
+		t.Errorf("ParseChain() = %v, %v", chain, err)
+	}
+
+	if s := AuthMethodTypeGoogleJwt.String(); s != "GoogleJwt" {
+		t.Errorf("String() = %s", s)
+	}
+	if s := AuthMethodType(99).String(); s != "AuthMethodType(99)" {
+		t.Errorf("String() = %s", s)
+	}
+}
+
+func TestConstantsJSON(t *testing.T) {
+	data, err := json.Marshal(MintWithAuthParams{AuthMethod: LitActionAuthMethod("token"), Scopes: []AuthMethodScope{AuthMethodScopeSignAnything}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"authMethod":{"authMethodType":2,"accessToken":"token"},"scopes":[1]}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	var decoded struct {
+		Type   AuthMethodType    `json:"type"`
+		Scopes []AuthMethodScope `json:"scopes"`
+		Future AuthMethodType    `json:"future"`
+	}
+	if err := json.Unmarshal([]byte(`{"type":"WebAuthn","scopes":[1,"PersonalSign"],"future":42}`), &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if decoded.Type != AuthMethodTypeWebAuthn || len(decoded.Scopes) != 2 || decoded.Scopes[1] != AuthMethodScopePersonalSign || decoded.Future != 42 {
+		t.Errorf("json.Unmarshal() = %+v", decoded)
+	}
+
+	var config LitNodeClientConfig
+	if err := json.Unmarshal([]byte(`{"litNetwork":"datil-dev"}`), &config); err != nil || config.LitNetwork != LitNetworkDatilDev {
+		t.Errorf("json.Unmarshal() = %+v, %v", config, err)
+	}
+	if err := json.Unmarshal([]byte(`{"litNetwork":"datil-prod"}`), &config); err == nil {
+		t.Error("Expected an error for an unknown network")
+	}
+}
@@ -0,0 +1,176 @@
+package lit_go_sdk
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors for common failure conditions reported by the JS SDK server.
+// A *LitError matches these with errors.Is.
+var (
+	// ErrNotInitialized is returned when the LitNodeClient or LitContractsClient
+	// has not been created on the server yet
+	ErrNotInitialized = errors.New("lit: client not initialized")
+
+	// ErrWalletNotSet is returned when an operation needs a wallet but no auth
+	// token has been set
+	ErrWalletNotSet = errors.New("lit: wallet not set")
+
+	// ErrAuthExpired is returned when the session sigs or auth sig sent with a
+	// request are expired or otherwise no longer accepted by the nodes
+	ErrAuthExpired = errors.New("lit: auth expired")
+
+	// ErrUnauthorized is returned when the JS SDK server rejects a request
+	// because it does not carry the server's shared secret
+	ErrUnauthorized = errors.New("lit: unauthorized by the JS SDK server")
+
+	// ErrAccessDenied is returned when the nodes refuse an operation because the
+	// caller does not satisfy its access control conditions or permissions
+	ErrAccessDenied = errors.New("lit: access denied")
+
+	// ErrInvalidParams is returned, before anything is sent to the server, when
+	// the parameters of a call are missing or inconsistent
+	ErrInvalidParams = errors.New("lit: invalid parameters")
+
+	// ErrInvalidAuthSig is returned by VerifyAuthSig for auth sigs that are
+	// not validly signed, or whose SIWE message is not valid
+	ErrInvalidAuthSig = errors.New("lit: invalid auth sig")
+)
+
+// LitError is returned when the JS SDK server answers a request with an error
+type LitError struct {
+	// StatusCode is the HTTP status code returned by the server
+	StatusCode int
+	// Endpoint is the server endpoint that was called, e.g. /litNodeClient/executeJs
+	Endpoint string
+	// Message is the error message reported by the server
+	Message string
+	// Stack is the JS stack trace, when the server sent one
+	Stack string
+	// Code is the Lit error code, e.g. NodeError or InvalidSessionSigs
+	Code string
+	// Kind is the Lit error kind, e.g. Validation or Unexpected
+	Kind string
+}
+
+// Error implements the error interface
+func (e *LitError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "lit: %s failed", e.Endpoint)
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&b, " with status %d", e.StatusCode)
+	}
+	if e.Code != "" {
+		fmt.Fprintf(&b, " (%s)", e.Code)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	return b.String()
+}
+
+// Is reports whether the error matches one of the package sentinel errors
+func (e *LitError) Is(target error) bool {
+	switch target {
+	case ErrWalletNotSet:
+		return e.isWalletNotSet()
+	case ErrNotInitialized:
+		return !e.isWalletNotSet() && e.messageContains("not initialized")
+	case ErrAuthExpired:
+		return e.codeIs("InvalidSessionSigs", "invalid_session_sigs", "InvalidAuthSig", "invalid_auth_sig") ||
+			e.messageContains(authExpiredMessages...)
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrAccessDenied:
+		return e.StatusCode == http.StatusForbidden ||
+			e.codeIs("NodeAccessControlConditionsReturnedNotAuthorized", "not_authorized", "Unauthorized") ||
+			e.messageContains("not authorized")
+	}
+	return false
+}
+
+func (e *LitError) isWalletNotSet() bool {
+	return e.messageContains("wallet not initialized") || e.messageContains("no lit auth token set")
+}
+
+// codeIs reports whether the error code matches any of codes, ignoring case
+func (e *LitError) codeIs(codes ...string) bool {
+	for _, code := range codes {
+		if strings.EqualFold(e.Code, code) {
+			return true
+		}
+	}
+	return false
+}
+
+// authExpiredMessages are the messages of expired auth reported without a
+// code. Other errors mention expiry too, e.g. of a signature request, so
+// matching "expired" alone is too broad.
+var authExpiredMessages = []string{
+	"session sigs expired",
+	"sessionsigs expired",
+	"session sig expired",
+	"session key expired",
+	"session expired",
+	"auth sig expired",
+	"authsig expired",
+}
+
+// messageContains reports whether the error message contains any of phrases,
+// ignoring case
+func (e *LitError) messageContains(phrases ...string) bool {
+	message := strings.ToLower(e.Message)
+	for _, phrase := range phrases {
+		if strings.Contains(message, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// newLitError builds a LitError from a server response. It returns nil if the
+// response does not describe an error.
+func newLitError(endpoint string, statusCode int, result map[string]interface{}) *LitError {
+	failed := statusCode >= http.StatusBadRequest
+	if success, ok := result["success"].(bool); ok && !success {
+		failed = true
+	}
+	if !failed {
+		return nil
+	}
+
+	litErr := &LitError{
+		StatusCode: statusCode,
+		Endpoint:   endpoint,
+	}
+
+	switch details := result["error"].(type) {
+	case string:
+		// {"success": false, "error": "LitNodeClient not initialized"}
+		litErr.Message = details
+	case map[string]interface{}:
+		// {"error": {"message": "...", "stack": "...", "code": "...", "kind": "..."}}
+		litErr.Message, _ = details["message"].(string)
+		litErr.Stack, _ = details["stack"].(string)
+		litErr.Code, _ = details["code"].(string)
+		litErr.Kind, _ = details["kind"].(string)
+	}
+
+	if litErr.Code == "" {
+		// Errors coming back from the nodes use errorCode/errorKind at the top level
+		litErr.Code, _ = result["errorCode"].(string)
+	}
+	if litErr.Kind == "" {
+		litErr.Kind, _ = result["errorKind"].(string)
+	}
+	if litErr.Message == "" {
+		litErr.Message, _ = result["message"].(string)
+	}
+	if litErr.Message == "" && statusCode >= http.StatusBadRequest {
+		litErr.Message = http.StatusText(statusCode)
+	}
+
+	return litErr
+}
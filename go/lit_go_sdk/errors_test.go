@@ -0,0 +1,132 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestPost_LitError(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantCode   string
+		wantStack  string
+		wantIs     error
+		wantNotIs  error
+		wantStatus int
+	}{
+		{
+			name:       "not initialized",
+			status:     http.StatusBadRequest,
+			body:       `{"success": false, "error": "LitNodeClient not initialized"}`,
+			wantIs:     ErrNotInitialized,
+			wantNotIs:  ErrWalletNotSet,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "wallet not set",
+			status:     http.StatusBadRequest,
+			body:       `{"success": false, "error": "Ethers wallet not initialized - Please set a Lit auth token."}`,
+			wantIs:     ErrWalletNotSet,
+			wantNotIs:  ErrNotInitialized,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "expired session sigs",
+			status:     http.StatusInternalServerError,
+			body:       `{"error": {"message": "Invalid sessionSigs", "stack": "Error: Invalid sessionSigs\n    at foo", "code": "InvalidSessionSigs", "kind": "Validation"}}`,
+			wantCode:   "InvalidSessionSigs",
+			wantStack:  "Error: Invalid sessionSigs\n    at foo",
+			wantIs:     ErrAuthExpired,
+			wantNotIs:  ErrAccessDenied,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "expired auth sig without a code",
+			status:     http.StatusInternalServerError,
+			body:       `{"error": {"message": "Auth sig expired at 2024-01-01T00:00:00Z"}}`,
+			wantIs:     ErrAuthExpired,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "expired signature request",
+			status:     http.StatusBadRequest,
+			body:       `{"success": false, "error": "Unknown or expired signature request"}`,
+			wantNotIs:  ErrAuthExpired,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "access denied",
+			status:     http.StatusInternalServerError,
+			body:       `{"error": {"message": "The access control conditions returned not authorized", "code": "NodeAccessControlConditionsReturnedNotAuthorized"}}`,
+			wantCode:   "NodeAccessControlConditionsReturnedNotAuthorized",
+			wantIs:     ErrAccessDenied,
+			wantNotIs:  ErrAuthExpired,
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:       "non json body",
+			status:     http.StatusBadGateway,
+			body:       `Bad Gateway`,
+			wantNotIs:  ErrNotInitialized,
+			wantStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+
+			result, err := client.ExecuteJsContext(context.Background(), ExecuteJsParams{Code: "code"})
+			if result != nil {
+				t.Errorf("Expected nil result, got %v", result)
+			}
+
+			var litErr *LitError
+			if !errors.As(err, &litErr) {
+				t.Fatalf("Expected *LitError, got %T: %v", err, err)
+			}
+			if litErr.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", litErr.StatusCode, tt.wantStatus)
+			}
+			if litErr.Endpoint != "/litNodeClient/executeJs" {
+				t.Errorf("Endpoint = %q, want /litNodeClient/executeJs", litErr.Endpoint)
+			}
+			if litErr.Message == "" {
+				t.Error("Expected a non-empty message")
+			}
+			if litErr.Code != tt.wantCode {
+				t.Errorf("Code = %q, want %q", litErr.Code, tt.wantCode)
+			}
+			if litErr.Stack != tt.wantStack {
+				t.Errorf("Stack = %q, want %q", litErr.Stack, tt.wantStack)
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("Expected errors.Is(err, %v) to be true", tt.wantIs)
+			}
+			if tt.wantNotIs != nil && errors.Is(err, tt.wantNotIs) {
+				t.Errorf("Expected errors.Is(err, %v) to be false", tt.wantNotIs)
+			}
+		})
+	}
+}
+
+func TestPost_SuccessIsNotAnError(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success": true, "sessionSigs": {}}`))
+	}))
+
+	sessionSigs, err := client.GetSessionSigsContext(context.Background(), SessionSigsParams{})
+	if err != nil {
+		t.Fatalf("GetSessionSigsContext() error = %v", err)
+	}
+	if sessionSigs == nil {
+		t.Error("Expected session sigs")
+	}
+}
@@ -0,0 +1,235 @@
+package lit_go_sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ExecuteJsParams represents the parameters for executing JS code. Exactly one
+// of Code and IpfsID must be set.
+type ExecuteJsParams struct {
+	// Code is the source of the Lit Action
+	Code string `json:"code,omitempty"`
+	// IpfsID is the IPFS CID of a deployed Lit Action, run instead of Code
+	IpfsID string `json:"ipfsId,omitempty"`
+	// IpfsOptions controls how the action is fetched from IPFS
+	IpfsOptions *IpfsOptions `json:"ipfsOptions,omitempty"`
+	// JsParams are exposed to the action as global variables
+	JsParams map[string]interface{} `json:"jsParams"`
+	// SessionSigs authorize the execution
+	SessionSigs SessionSigs `json:"sessionSigs"`
+	// AuthMethods are passed to the action, which can check them with
+	// Lit.Auth and use them to sign with PKPs they control
+	AuthMethods []AuthMethod `json:"authMethods,omitempty"`
+	// ResponseStrategy selects which of the nodes' responses is returned.
+	// If nil, ExecuteJs sends ResponseStrategyMostCommon rather than leave the
+	// choice to the JS SDK.
+	ResponseStrategy *ResponseStrategy `json:"responseStrategy,omitempty"`
+	// UseSingleNode runs the action on a single node instead of all of them.
+	// Actions run this way cannot sign or decrypt.
+	UseSingleNode bool `json:"useSingleNode,omitempty"`
+}
+
+// Validate checks that the parameters are consistent. ExecuteJs calls it
+// before sending anything to the server; the error wraps ErrInvalidParams.
+func (p ExecuteJsParams) Validate() error {
+	if (p.Code == "") == (p.IpfsID == "") {
+		return fmt.Errorf("%w: exactly one of Code or IpfsID must be set", ErrInvalidParams)
+	}
+	if p.IpfsOptions != nil && p.IpfsID == "" {
+		return fmt.Errorf("%w: IpfsOptions needs IpfsID", ErrInvalidParams)
+	}
+	if p.ResponseStrategy != nil {
+		return p.ResponseStrategy.validate()
+	}
+	return nil
+}
+
+// IpfsOptions controls how a Lit Action is fetched from IPFS
+type IpfsOptions struct {
+	// OverwriteCode makes the nodes fetch the code from GatewayURL themselves
+	OverwriteCode bool `json:"overwriteCode,omitempty"`
+	// GatewayURL is the IPFS gateway to fetch the code from, of the form
+	// https://host/ipfs/
+	GatewayURL string `json:"gatewayUrl,omitempty"`
+}
+
+// ResponseStrategyKind names a strategy for picking among the nodes' responses
+type ResponseStrategyKind string
+
+const (
+	// ResponseStrategyMostCommon returns the response most nodes agree on
+	ResponseStrategyMostCommon ResponseStrategyKind = "mostCommon"
+	// ResponseStrategyLeastCommon returns the response fewest nodes agree on
+	ResponseStrategyLeastCommon ResponseStrategyKind = "leastCommon"
+	// ResponseStrategyCustom returns the response chosen by a custom filter
+	ResponseStrategyCustom ResponseStrategyKind = "custom"
+)
+
+// ResponseFilter picks the response ExecuteJs returns from the responses of
+// every node
+type ResponseFilter func(responses []ExecuteJsResponse) (ExecuteJsResponse, error)
+
+// ResponseStrategy selects which of the nodes' responses ExecuteJs returns
+type ResponseStrategy struct {
+	// Strategy is the kind of strategy
+	Strategy ResponseStrategyKind `json:"strategy"`
+	// CustomFilter is, for ResponseStrategyCustom, the filter choosing the
+	// response. The server returns every node's response and the filter runs
+	// in this process, so no code is sent to the server.
+	CustomFilter ResponseFilter `json:"-"`
+}
+
+// validate checks the strategy is known and has a filter only when custom
+func (s ResponseStrategy) validate() error {
+	switch s.Strategy {
+	case ResponseStrategyMostCommon, ResponseStrategyLeastCommon:
+		if s.CustomFilter != nil {
+			return fmt.Errorf("%w: CustomFilter is only used with ResponseStrategyCustom", ErrInvalidParams)
+		}
+	case ResponseStrategyCustom:
+		if s.CustomFilter == nil {
+			return fmt.Errorf("%w: ResponseStrategyCustom needs a CustomFilter", ErrInvalidParams)
+		}
+	default:
+		return fmt.Errorf("%w: unknown response strategy %q", ErrInvalidParams, s.Strategy)
+	}
+	return nil
+}
+
+// filter replaces the response of result, which holds the responses of every
+// node for ResponseStrategyCustom, with the one chosen by the custom filter
+func (s ResponseStrategy) filter(result *ExecuteJsResult) error {
+	if s.Strategy != ResponseStrategyCustom {
+		return nil
+	}
+	responses, err := nodeResponses(result.Response)
+	if err != nil {
+		return err
+	}
+	response, err := s.CustomFilter(responses)
+	if err != nil {
+		return fmt.Errorf("lit: custom response filter failed: %w", err)
+	}
+	result.Response = response
+	return nil
+}
+
+// nodeResponses decodes the JSON array of the nodes' raw responses, which the
+// JS SDK may return as a JSON string. Each response is parsed like the JS SDK
+// parses the response it picks.
+func nodeResponses(response ExecuteJsResponse) ([]ExecuteJsResponse, error) {
+	data := []byte(response)
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		data = []byte(s)
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode node responses: %w", err)
+	}
+	responses := make([]ExecuteJsResponse, len(raw))
+	for i, r := range raw {
+		if json.Unmarshal(r, &s) == nil && json.Valid([]byte(s)) {
+			r = json.RawMessage(s)
+		}
+		responses[i] = ExecuteJsResponse(r)
+	}
+	return responses, nil
+}
+
+// ExecuteJsResult is the result of running a Lit Action
+type ExecuteJsResult struct {
+	// Success reports whether the nodes ran the action successfully
+	Success bool `json:"success"`
+	// Response is the value the action passed to Lit.Actions.setResponse, as
+	// raw JSON. Use Unmarshal or String to read it.
+	Response ExecuteJsResponse `json:"response"`
+	// Logs holds what the action logged with console.log
+	Logs string `json:"logs"`
+	// Signatures maps the names the action signed under, with
+	// Lit.Actions.signEcdsa and the like, to the combined signatures
+	Signatures map[string]Signature `json:"signatures"`
+	// Decryptions holds the results of Lit.Actions.decryptAndCombine calls
+	Decryptions []json.RawMessage `json:"decryptions"`
+	// Claims maps the names the action claimed keys under to the claim
+	Claims map[string]Claim `json:"claims"`
+}
+
+// ExecuteJsResponse is the raw JSON value set as the response of a Lit Action.
+// The JS SDK parses responses that are valid JSON, so an action calling
+// setResponse({response: JSON.stringify(obj)}) yields obj here, and any other
+// response yields a JSON string.
+type ExecuteJsResponse json.RawMessage
+
+// MarshalJSON implements json.Marshaler
+func (r ExecuteJsResponse) MarshalJSON() ([]byte, error) {
+	if len(r) == 0 {
+		return []byte("null"), nil
+	}
+	return r, nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (r *ExecuteJsResponse) UnmarshalJSON(data []byte) error {
+	*r = append((*r)[:0], data...)
+	return nil
+}
+
+// Unmarshal decodes the response into v
+func (r ExecuteJsResponse) Unmarshal(v interface{}) error {
+	data, _ := r.MarshalJSON()
+	return json.Unmarshal(data, v)
+}
+
+// String returns the response if it is a JSON string, or its raw JSON otherwise
+func (r ExecuteJsResponse) String() string {
+	var s string
+	if err := json.Unmarshal(r, &s); err == nil {
+		return s
+	}
+	return string(r)
+}
+
+// IsNull reports whether the action did not set a response
+func (r ExecuteJsResponse) IsNull() bool {
+	trimmed := bytes.TrimSpace(r)
+	return len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null"))
+}
+
+// Signature is an ECDSA signature combined from the nodes' signature shares
+type Signature struct {
+	// R is the hex encoded r value
+	R string `json:"r"`
+	// S is the hex encoded s value
+	S string `json:"s"`
+	// Recid is the recovery ID, 0 or 1
+	Recid int `json:"recid"`
+	// Signature is the hex encoded signature, r, s and v concatenated
+	Signature string `json:"signature"`
+	// PublicKey is the hex encoded public key of the PKP that signed
+	PublicKey string `json:"publicKey"`
+	// DataSigned is the hex encoded data that was signed
+	DataSigned string `json:"dataSigned"`
+}
+
+// V returns the Ethereum recovery value of the signature, 27 or 28
+func (s Signature) V() int {
+	return s.Recid + 27
+}
+
+// Claim is a key claimed by a Lit Action with Lit.Actions.claimKey
+type Claim struct {
+	// Signatures are the nodes' signatures over the claim
+	Signatures []ClaimSignature `json:"signatures"`
+	// DerivedKeyID is the ID of the claimed key
+	DerivedKeyID string `json:"derivedKeyId"`
+}
+
+// ClaimSignature is a node's signature over a key claim
+type ClaimSignature struct {
+	R string `json:"r"`
+	S string `json:"s"`
+	V int    `json:"v"`
+}
@@ -0,0 +1,205 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestExecuteJsContext_DecodesResult(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"success": true,
+			"response": {"price": 42, "symbol": "ETH"},
+			"logs": "fetching price\n",
+			"signatures": {
+				"sig1": {
+					"r": "aa", "s": "bb", "recid": 1,
+					"signature": "0xaabb1c",
+					"publicKey": "04cc",
+					"dataSigned": "dd"
+				}
+			},
+			"decryptions": [],
+			"claims": {
+				"claim1": {"signatures": [{"r": "0x01", "s": "0x02", "v": 27}], "derivedKeyId": "key"}
+			}
+		}`))
+	}))
+
+	result, err := client.ExecuteJsContext(context.Background(), ExecuteJsParams{Code: "code"})
+	if err != nil {
+		t.Fatalf("ExecuteJsContext() error = %v", err)
+	}
+	if !result.Success || result.Logs != "fetching price\n" {
+		t.Errorf("Success, Logs = %v, %q", result.Success, result.Logs)
+	}
+
+	var price struct {
+		Price  int    `json:"price"`
+		Symbol string `json:"symbol"`
+	}
+	if err := result.Response.Unmarshal(&price); err != nil {
+		t.Fatalf("Response.Unmarshal() error = %v", err)
+	}
+	if price.Price != 42 || price.Symbol != "ETH" {
+		t.Errorf("Response = %+v, want price 42 for ETH", price)
+	}
+
+	sig, ok := result.Signatures["sig1"]
+	if !ok {
+		t.Fatal("Expected signature sig1")
+	}
+	if sig.R != "aa" || sig.S != "bb" || sig.V() != 28 || sig.PublicKey != "04cc" || sig.DataSigned != "dd" {
+		t.Errorf("Signatures[sig1] = %+v", sig)
+	}
+	if claim := result.Claims["claim1"]; claim.DerivedKeyID != "key" || len(claim.Signatures) != 1 || claim.Signatures[0].V != 27 {
+		t.Errorf("Claims[claim1] = %+v", claim)
+	}
+}
+
+func TestExecuteJsResponse_String(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{raw: `"Test successful"`, want: "Test successful"},
+		{raw: `{"a":1}`, want: `{"a":1}`},
+		{raw: ``, want: ``},
+	}
+	for _, tt := range tests {
+		if got := ExecuteJsResponse(tt.raw).String(); got != tt.want {
+			t.Errorf("ExecuteJsResponse(%q).String() = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+	if !ExecuteJsResponse("null").IsNull() || ExecuteJsResponse(`""`).IsNull() {
+		t.Error("IsNull() should only report a missing or null response")
+	}
+}
+
+func TestExecuteJsParams_Validate(t *testing.T) {
+	first := func(responses []ExecuteJsResponse) (ExecuteJsResponse, error) { return responses[0], nil }
+	tests := []struct {
+		name    string
+		params  ExecuteJsParams
+		wantErr bool
+	}{
+		{"code", ExecuteJsParams{Code: "code"}, false},
+		{"ipfs id", ExecuteJsParams{IpfsID: "QmHash", IpfsOptions: &IpfsOptions{OverwriteCode: true}}, false},
+		{"neither", ExecuteJsParams{}, true},
+		{"both", ExecuteJsParams{Code: "code", IpfsID: "QmHash"}, true},
+		{"ipfs options without ipfs id", ExecuteJsParams{Code: "code", IpfsOptions: &IpfsOptions{}}, true},
+		{"least common", ExecuteJsParams{Code: "code", ResponseStrategy: &ResponseStrategy{Strategy: ResponseStrategyLeastCommon}}, false},
+		{"custom", ExecuteJsParams{Code: "code", ResponseStrategy: &ResponseStrategy{Strategy: ResponseStrategyCustom, CustomFilter: first}}, false},
+		{"custom without filter", ExecuteJsParams{Code: "code", ResponseStrategy: &ResponseStrategy{Strategy: ResponseStrategyCustom}}, true},
+		{"filter without custom", ExecuteJsParams{Code: "code", ResponseStrategy: &ResponseStrategy{Strategy: ResponseStrategyMostCommon, CustomFilter: first}}, true},
+		{"unknown strategy", ExecuteJsParams{Code: "code", ResponseStrategy: &ResponseStrategy{Strategy: "random"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.params.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("Expected errors.Is(err, ErrInvalidParams), got %v", err)
+			}
+		})
+	}
+}
+
+func TestExecuteJsContext_InvalidParamsNotSent(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request to be sent")
+	}))
+
+	_, err := client.ExecuteJsContext(context.Background(), ExecuteJsParams{Code: "code", IpfsID: "QmHash"})
+	if !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams, got %v", err)
+	}
+}
+
+func TestExecuteJsParams_JSON(t *testing.T) {
+	data, err := json.Marshal(ExecuteJsParams{
+		IpfsID:           "QmHash",
+		AuthMethods:      []AuthMethod{{AuthMethodType: 1, AccessToken: "token"}},
+		ResponseStrategy: &ResponseStrategy{Strategy: ResponseStrategyCustom, CustomFilter: func(r []ExecuteJsResponse) (ExecuteJsResponse, error) { return r[0], nil }},
+		UseSingleNode:    true,
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"ipfsId":"QmHash","jsParams":null,"sessionSigs":null,"authMethods":[{"authMethodType":1,"accessToken":"token"}],"responseStrategy":{"strategy":"custom"},"useSingleNode":true}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestExecuteJsContext_DefaultResponseStrategy(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"responseStrategy":{"strategy":"mostCommon"}`) {
+			t.Errorf("Expected the most common strategy to be sent, got %s", body)
+		}
+		w.Write([]byte(`{"success": true}`))
+	}))
+
+	params := ExecuteJsParams{Code: "code", SessionSigs: SessionSigs{"node": {}}}
+	if _, err := client.ExecuteJs(params); err != nil {
+		t.Fatalf("ExecuteJs() error = %v", err)
+	}
+	if params.ResponseStrategy != nil {
+		t.Error("Expected the params to be unchanged")
+	}
+}
+
+func TestExecuteJsContext_CustomResponseFilter(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "customFilter") {
+			t.Errorf("Expected no filter to be sent, got %s", body)
+		}
+		// The server collects the raw responses of every node as a JSON string
+		w.Write([]byte(`{"success": true, "response": "[\"ok\",\"{\\\"n\\\":2}\",\"3\"]", "logs": ""}`))
+	}))
+
+	var got []ExecuteJsResponse
+	result, err := client.ExecuteJsContext(context.Background(), ExecuteJsParams{
+		Code: "code",
+		ResponseStrategy: &ResponseStrategy{
+			Strategy: ResponseStrategyCustom,
+			CustomFilter: func(responses []ExecuteJsResponse) (ExecuteJsResponse, error) {
+				got = responses
+				return responses[1], nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ExecuteJsContext() error = %v", err)
+	}
+	want := []string{`"ok"`, `{"n":2}`, `3`}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d responses, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if string(got[i]) != want[i] {
+			t.Errorf("responses[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+	if string(result.Response) != `{"n":2}` {
+		t.Errorf("Response = %s, want the filtered response", result.Response)
+	}
+
+	failing := &ResponseStrategy{
+		Strategy:     ResponseStrategyCustom,
+		CustomFilter: func([]ExecuteJsResponse) (ExecuteJsResponse, error) { return nil, errors.New("no agreement") },
+	}
+	if _, err := client.ExecuteJsContext(context.Background(), ExecuteJsParams{Code: "code", ResponseStrategy: failing}); err == nil || !strings.Contains(err.Error(), "no agreement") {
+		t.Errorf("ExecuteJsContext() error = %v, want the filter's error", err)
+	}
+}
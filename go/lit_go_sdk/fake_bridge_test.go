@@ -0,0 +1,142 @@
+package lit_go_sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// fakeBridgeEnv makes the test binary act as a minimal JS SDK server when it
+// is started as the "node" executable by a NodeServer
+const fakeBridgeEnv = "LIT_GO_SDK_FAKE_BRIDGE"
+
+// fakeBridgeVersionEnv overrides the version the fake bridge reports in its handshake
+const fakeBridgeVersionEnv = "LIT_GO_SDK_FAKE_BRIDGE_VERSION"
+
+// fakeBridgeIgnoreTermEnv makes the fake bridge ignore SIGTERM
+const fakeBridgeIgnoreTermEnv = "LIT_GO_SDK_FAKE_BRIDGE_IGNORE_TERM"
+
+func init() {
+	switch os.Getenv(fakeBridgeEnv) {
+	case "1":
+		if os.Getenv(fakeBridgeIgnoreTermEnv) == "1" {
+			signal.Ignore(syscall.SIGTERM)
+		}
+		runFakeBridge()
+		os.Exit(0)
+	case "child":
+		// A child process spawned by the fake bridge, see /spawnChild
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+}
+
+// runFakeBridge serves the endpoints the client needs to come up on $PORT, or
+// on the Unix socket at $SOCKET_PATH if set.
+// GetProperty returns the endpoints this process has been called on, /shutdown
+// exits like the real server does, /crash makes the process exit with status 3
+// and /spawnChild starts a child process that sleeps for a minute.
+func runFakeBridge() {
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/isReady", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ready": true}`))
+	})
+	mux.HandleFunc("/handshake", func(w http.ResponseWriter, r *http.Request) {
+		version := BridgeVersion
+		if v, ok := os.LookupEnv(fakeBridgeVersionEnv); ok {
+			version = v
+		}
+		json.NewEncoder(w).Encode(BridgeInfo{
+			BridgeVersion: version,
+			InstanceID:    fmt.Sprintf("fake-%d", os.Getpid()),
+			PID:           os.Getpid(),
+			UID:           os.Getuid(),
+		})
+	})
+	mux.HandleFunc("/shutdown", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"success": true}`))
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			os.Exit(0)
+		}()
+	})
+	mux.HandleFunc("/crash", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Println("fake bridge crashing")
+		os.Exit(3)
+	})
+	mux.HandleFunc("/spawnChild", func(w http.ResponseWriter, r *http.Request) {
+		child := exec.Command(os.Args[0])
+		child.Env = append(os.Environ(), fakeBridgeEnv+"=child")
+		if err := child.Start(); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "pid": child.Process.Pid})
+	})
+	mux.HandleFunc("/litNodeClient/getProperty", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "property": calls})
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.URL.Path)
+		mu.Unlock()
+		w.Write([]byte(`{"success": true}`))
+	})
+
+	// Like the real server, require the launch secret on every request
+	secret := os.Getenv(bridgeSecretEnv)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if secret != "" && r.Header.Get("Authorization") != "Bearer "+secret {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"success": false, "error": "Unauthorized"}`))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+
+	network, address := "tcp", "127.0.0.1:"+os.Getenv("PORT")
+	if socketPath := os.Getenv(bridgeSocketEnv); socketPath != "" {
+		network, address = "unix", socketPath
+	}
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	fmt.Println("fake bridge listening")
+	if err := http.Serve(listener, handler); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// fakeBridgeOptions returns the options that start the fake bridge instead of Node.js
+func fakeBridgeOptions(t *testing.T) []Option {
+	t.Helper()
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatalf("failed to find test executable: %v", err)
+	}
+	return []Option{
+		WithEphemeralPort(),
+		WithNodePath(executable),
+		WithServerPath(executable),
+		WithEnv(fakeBridgeEnv + "=1"),
+	}
+}
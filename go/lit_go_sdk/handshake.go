@@ -0,0 +1,141 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// BridgeVersion is the version of the JS SDK server protocol this module
+// speaks. A running server reporting a different version is not reused.
+const BridgeVersion = "1.1.0"
+
+// ErrBridgeMismatch is returned by NewLitNodeClient when the server already
+// running on the port is not one the client can reuse. The error is a
+// *BridgeMismatchError.
+var ErrBridgeMismatch = errors.New("lit: running JS SDK server cannot be reused")
+
+// BridgeInfo identifies a running JS SDK server
+type BridgeInfo struct {
+	// BridgeVersion is the version of the server, compared against BridgeVersion
+	BridgeVersion string `json:"bridgeVersion"`
+	// Packages are the versions of the Lit JS SDK packages bundled in the server
+	Packages BridgePackages `json:"packages"`
+	// InstanceID is a random ID generated each time the server starts
+	InstanceID string `json:"instanceId"`
+	// PID is the process ID of the server
+	PID int `json:"pid"`
+	// UID is the user ID the server runs as, or -1 where there is none
+	UID int `json:"uid"`
+}
+
+// BridgePackages are the versions of the Lit JS SDK packages and Node.js
+// used by a running server. A version is "unknown" if the server could not
+// read it, and empty if the server predates reporting it.
+type BridgePackages struct {
+	// LitNodeClient is the version of @lit-protocol/lit-node-client-nodejs
+	LitNodeClient string `json:"@lit-protocol/lit-node-client-nodejs"`
+	// AuthHelpers is the version of @lit-protocol/auth-helpers
+	AuthHelpers string `json:"@lit-protocol/auth-helpers"`
+	// ContractsSDK is the version of @lit-protocol/contracts-sdk
+	ContractsSDK string `json:"@lit-protocol/contracts-sdk"`
+	// Constants is the version of @lit-protocol/constants
+	Constants string `json:"@lit-protocol/constants"`
+	// Node is the version of Node.js
+	Node string `json:"node"`
+}
+
+// BridgeMismatchError describes a running server that the client refused to reuse
+type BridgeMismatchError struct {
+	// Info is what the server reported about itself, nil if it does not
+	// support the handshake
+	Info *BridgeInfo
+	// Reason explains why the server was refused
+	Reason string
+	// Err is the error returned by the handshake request, if any
+	Err error
+}
+
+// Error implements the error interface
+func (e *BridgeMismatchError) Error() string {
+	return fmt.Sprintf("lit: running JS SDK server cannot be reused: %s", e.Reason)
+}
+
+// Is reports whether target is ErrBridgeMismatch
+func (e *BridgeMismatchError) Is(target error) bool {
+	return target == ErrBridgeMismatch
+}
+
+// Unwrap returns the underlying error
+func (e *BridgeMismatchError) Unwrap() error {
+	return e.Err
+}
+
+// Handshake asks the server to identify itself
+func (c *LitNodeClient) Handshake() (*BridgeInfo, error) {
+	return c.HandshakeContext(context.Background())
+}
+
+// HandshakeContext is like Handshake but uses ctx for the request to the server
+func (c *LitNodeClient) HandshakeContext(ctx context.Context) (*BridgeInfo, error) {
+	result, err := c.send(ctx, "/handshake", nil)
+	if err != nil {
+		return nil, err
+	}
+	info := &BridgeInfo{UID: -1}
+	if err := decodeResult(result, info); err != nil {
+		return nil, fmt.Errorf("failed to decode handshake: %w", err)
+	}
+	return info, nil
+}
+
+// verifyBridge checks that the running server is a bridge of the expected
+// version owned by the current user
+func (c *LitNodeClient) verifyBridge(ctx context.Context) error {
+	info, err := c.HandshakeContext(ctx)
+	if err != nil {
+		return &BridgeMismatchError{Reason: "handshake failed, the server may predate version " + BridgeVersion, Err: err}
+	}
+	if info.BridgeVersion != BridgeVersion {
+		return &BridgeMismatchError{
+			Info:   info,
+			Reason: fmt.Sprintf("server version %q does not match expected version %q", info.BridgeVersion, BridgeVersion),
+		}
+	}
+	if uid := os.Getuid(); uid != -1 && info.UID != uid {
+		return &BridgeMismatchError{
+			Info:   info,
+			Reason: fmt.Sprintf("server runs as user %d, not as the current user %d", info.UID, uid),
+		}
+	}
+	return nil
+}
+
+// shutdownBridge asks a running server to exit and waits until it no longer
+// answers, so a new server can take over its port
+func (c *LitNodeClient) shutdownBridge(timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if _, err := c.send(ctx, "/shutdown", nil); err != nil {
+		return fmt.Errorf("failed to shut down running server: %w", err)
+	}
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if !c.isServerRunning(ctx) {
+			if ctx.Err() != nil {
+				return fmt.Errorf("running server did not shut down within timeout period")
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("running server did not shut down within timeout period")
+		case <-ticker.C:
+		}
+	}
+}
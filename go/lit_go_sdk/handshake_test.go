@@ -0,0 +1,125 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+)
+
+// startFakeBridge starts a fake bridge through a client that does not restart
+// it, and returns that client
+func startFakeBridge(t *testing.T, opts ...Option) *LitNodeClient {
+	t.Helper()
+	client, err := NewLitNodeClient(append(append(fakeBridgeOptions(t), WithAutoRestart(false)), opts...)...)
+	if err != nil {
+		t.Fatalf("NewLitNodeClient() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestHandshake(t *testing.T) {
+	client := startFakeBridge(t)
+
+	info, err := client.HandshakeContext(context.Background())
+	if err != nil {
+		t.Fatalf("HandshakeContext() error = %v", err)
+	}
+	if info.BridgeVersion != BridgeVersion {
+		t.Errorf("BridgeVersion = %q, want %q", info.BridgeVersion, BridgeVersion)
+	}
+	if info.PID != client.Server().cmd.Process.Pid {
+		t.Errorf("PID = %d, want %d", info.PID, client.Server().cmd.Process.Pid)
+	}
+	if info.InstanceID == "" {
+		t.Error("Expected an instance ID")
+	}
+}
+
+func TestHandshake_Packages(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"bridgeVersion": "1.1.0", "packages": {"@lit-protocol/lit-node-client-nodejs": "7.0.5", "@lit-protocol/auth-helpers": "7.0.5", "@lit-protocol/contracts-sdk": "7.0.4", "@lit-protocol/constants": "7.0.5", "node": "20.11.0"}, "instanceId": "abc", "pid": 1, "uid": 1000}`))
+	}))
+
+	info, err := client.HandshakeContext(context.Background())
+	if err != nil {
+		t.Fatalf("HandshakeContext() error = %v", err)
+	}
+	want := BridgePackages{LitNodeClient: "7.0.5", AuthHelpers: "7.0.5", ContractsSDK: "7.0.4", Constants: "7.0.5", Node: "20.11.0"}
+	if info.Packages != want {
+		t.Errorf("Packages = %+v, want %+v", info.Packages, want)
+	}
+}
+
+func TestNewLitNodeClient_ReusesMatchingBridge(t *testing.T) {
+	running := startFakeBridge(t)
+
+	client, err := NewLitNodeClient(WithPort(running.port), WithBridgeSecret(running.secret))
+	if err != nil {
+		t.Fatalf("NewLitNodeClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if client.Server() != nil {
+		t.Error("Expected the running server to be reused")
+	}
+}
+
+func TestNewLitNodeClient_RefusesMismatchedBridge(t *testing.T) {
+	running := startFakeBridge(t, WithEnv(fakeBridgeVersionEnv+"=0.0.1"))
+
+	_, err := NewLitNodeClient(WithPort(running.port), WithBridgeSecret(running.secret))
+	if !errors.Is(err, ErrBridgeMismatch) {
+		t.Fatalf("NewLitNodeClient() error = %v, want ErrBridgeMismatch", err)
+	}
+	var mismatch *BridgeMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Info == nil {
+		t.Fatalf("Expected a *BridgeMismatchError with the server's info, got %v", err)
+	}
+	if mismatch.Info.BridgeVersion != "0.0.1" {
+		t.Errorf("Info.BridgeVersion = %q, want %q", mismatch.Info.BridgeVersion, "0.0.1")
+	}
+}
+
+func TestNewLitNodeClient_RefusesBridgeWithAnotherSecret(t *testing.T) {
+	running := startFakeBridge(t)
+
+	start := time.Now()
+	_, err := NewLitNodeClient(WithPort(running.port), WithBridgeSecret("not-the-secret"), WithStartupTimeout(10*time.Second))
+	if !errors.Is(err, ErrUnauthorized) || !errors.Is(err, ErrBridgeMismatch) {
+		t.Fatalf("NewLitNodeClient() error = %v, want ErrUnauthorized and ErrBridgeMismatch", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Expected NewLitNodeClient() to fail without waiting for a server, took %v", elapsed)
+	}
+}
+
+func TestNewLitNodeClient_RestartsMismatchedBridge(t *testing.T) {
+	running := startFakeBridge(t, WithEnv(fakeBridgeVersionEnv+"=0.0.1"))
+
+	opts := append(fakeBridgeOptions(t),
+		WithPort(running.port),
+		WithBridgeSecret(running.secret),
+		WithRestartMismatchedBridge(true),
+	)
+	client, err := NewLitNodeClient(opts...)
+	if err != nil {
+		t.Fatalf("NewLitNodeClient() error = %v", err)
+	}
+	defer client.Close()
+
+	<-running.Server().Done()
+	if client.Server() == nil {
+		t.Fatal("Expected the client to start its own server")
+	}
+	info, err := client.HandshakeContext(context.Background())
+	if err != nil {
+		t.Fatalf("HandshakeContext() error = %v", err)
+	}
+	if info.BridgeVersion != BridgeVersion || info.UID != os.Getuid() {
+		t.Errorf("Handshake() = %+v, want version %q for the current user", info, BridgeVersion)
+	}
+}
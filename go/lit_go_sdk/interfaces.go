@@ -0,0 +1,68 @@
+package lit_go_sdk
+
+import "context"
+
+// Executor runs Lit Actions on the Lit network
+type Executor interface {
+	ExecuteJs(params ExecuteJsParams) (*ExecuteJsResult, error)
+	ExecuteJsContext(ctx context.Context, params ExecuteJsParams) (*ExecuteJsResult, error)
+}
+
+// PKPSigner signs data with a PKP
+type PKPSigner interface {
+	PKPSign(params PKPSignParams) (map[string]interface{}, error)
+	PKPSignContext(ctx context.Context, params PKPSignParams) (map[string]interface{}, error)
+}
+
+// Encryptor encrypts and decrypts data under access control conditions
+type Encryptor interface {
+	EncryptString(params EncryptStringParams) (map[string]interface{}, error)
+	EncryptStringContext(ctx context.Context, params EncryptStringParams) (map[string]interface{}, error)
+	DecryptString(params DecryptStringParams) (map[string]interface{}, error)
+	DecryptStringContext(ctx context.Context, params DecryptStringParams) (map[string]interface{}, error)
+}
+
+// Minter mints PKPs through the Lit contracts
+type Minter interface {
+	NewLitContractsClient(config LitContractsClientConfig) (map[string]interface{}, error)
+	NewLitContractsClientContext(ctx context.Context, config LitContractsClientConfig) (map[string]interface{}, error)
+	MintWithAuth(params MintWithAuthParams) (map[string]interface{}, error)
+	MintWithAuthContext(ctx context.Context, params MintWithAuthParams) (map[string]interface{}, error)
+}
+
+// Authenticator obtains session signatures and auth signatures
+type Authenticator interface {
+	SetAuthToken(authToken string) (map[string]interface{}, error)
+	SetAuthTokenContext(ctx context.Context, authToken string) (map[string]interface{}, error)
+	SetSigner(signer Signer) (map[string]interface{}, error)
+	SetSignerContext(ctx context.Context, signer Signer) (map[string]interface{}, error)
+	GetSessionSigs(params SessionSigsParams) (SessionSigs, error)
+	GetSessionSigsContext(ctx context.Context, params SessionSigsParams) (SessionSigs, error)
+	CreateSiweMessage(params CreateSiweMessageParams) (map[string]interface{}, error)
+	CreateSiweMessageContext(ctx context.Context, params CreateSiweMessageParams) (map[string]interface{}, error)
+	GenerateAuthSig(toSign string) (*AuthSig, error)
+	GenerateAuthSigContext(ctx context.Context, toSign string) (*AuthSig, error)
+}
+
+// LitClient covers the Lit operations of a LitNodeClient, so code can depend
+// on it instead of the concrete type and be tested with a mock such as
+// littest.Mock. Methods managing the Node.js server process are left out.
+type LitClient interface {
+	Executor
+	PKPSigner
+	Encryptor
+	Minter
+	Authenticator
+
+	New(config LitNodeClientConfig) (map[string]interface{}, error)
+	NewContext(ctx context.Context, config LitNodeClientConfig) (map[string]interface{}, error)
+	Connect() (map[string]interface{}, error)
+	ConnectContext(ctx context.Context) (map[string]interface{}, error)
+	Disconnect() (map[string]interface{}, error)
+	DisconnectContext(ctx context.Context) (map[string]interface{}, error)
+	GetProperty(property string) (map[string]interface{}, error)
+	GetPropertyContext(ctx context.Context, property string) (map[string]interface{}, error)
+	Close() error
+}
+
+var _ LitClient = (*LitNodeClient)(nil)
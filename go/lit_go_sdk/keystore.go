@@ -0,0 +1,192 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// KeystoreSigner is a TransactionSigner for a key stored encrypted in a Web3
+// Secret Storage (keystore v3) file. The key is only decrypted while signing
+// and zeroed right after, so every signature pays for the key derivation of
+// the file (about a second with the standard scrypt parameters).
+//
+// The signer keeps the passphrase and the encrypted key in memory to sign
+// again, so whoever can read the process memory can still decrypt the key:
+// zeroing it only shortens the time it is held in the clear. Close clears
+// them once the signer is no longer needed.
+type KeystoreSigner struct {
+	mu         sync.Mutex
+	keyJSON    []byte
+	passphrase []byte
+	address    common.Address
+}
+
+var _ TransactionSigner = (*KeystoreSigner)(nil)
+
+// errKeystoreSignerClosed is returned when signing after Close
+var errKeystoreSignerClosed = errors.New("lit: keystore signer is closed")
+
+// NewKeystoreSigner returns a signer for the keystore v3 JSON keyJSON,
+// encrypted with passphrase. It checks the passphrase by decrypting the key
+// once.
+func NewKeystoreSigner(keyJSON []byte, passphrase string) (*KeystoreSigner, error) {
+	s := &KeystoreSigner{keyJSON: append([]byte(nil), keyJSON...), passphrase: []byte(passphrase)}
+	err := s.withKey(func(key *ecdsa.PrivateKey) error {
+		s.address = crypto.PubkeyToAddress(key.PublicKey)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// KeystoreSignerFromFile returns a signer for the keystore v3 file at path,
+// encrypted with passphrase
+func KeystoreSignerFromFile(path, passphrase string) (*KeystoreSigner, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("lit: failed to read keystore file: %w", err)
+	}
+	return NewKeystoreSigner(keyJSON, passphrase)
+}
+
+// KeystoreSignerFromDir returns a signer for the account with address in the
+// go-ethereum keystore directory dir, encrypted with passphrase. A zero
+// address selects the only account of the directory. Only the file of the
+// account is decrypted.
+func KeystoreSignerFromDir(dir string, address common.Address, passphrase string) (*KeystoreSigner, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("lit: failed to open keystore directory: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		// Skip what go-ethereum skips: editor backups, dotfiles and subdirectories
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		fileAddress, ok := keystoreFileAddress(path)
+		if !ok {
+			continue
+		}
+		if address == (common.Address{}) || fileAddress == address {
+			paths = append(paths, path)
+		}
+	}
+
+	switch {
+	case len(paths) == 0 && address != (common.Address{}):
+		return nil, fmt.Errorf("%w: no account %s in keystore directory %s", ErrInvalidParams, address, dir)
+	case len(paths) != 1 && address == (common.Address{}):
+		return nil, fmt.Errorf("%w: keystore directory %s holds %d accounts, select one by address", ErrInvalidParams, dir, len(paths))
+	case len(paths) != 1:
+		return nil, fmt.Errorf("%w: account %s has %d files in keystore directory %s", ErrInvalidParams, address, len(paths), dir)
+	}
+	signer, err := KeystoreSignerFromFile(paths[0], passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if address != (common.Address{}) && signer.Address() != address {
+		return nil, fmt.Errorf("%w: keystore file %s holds the key of %s, not %s", ErrInvalidParams, paths[0], signer.Address(), address)
+	}
+	return signer, nil
+}
+
+// keystoreFileAddress returns the address recorded in the keystore file at
+// path, without decrypting it. ok is false if it is not a keystore file.
+func keystoreFileAddress(path string) (address common.Address, ok bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return common.Address{}, false
+	}
+	var key struct {
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal(data, &key); err != nil || !common.IsHexAddress(key.Address) {
+		return common.Address{}, false
+	}
+	return common.HexToAddress(key.Address), true
+}
+
+// Address implements Signer
+func (s *KeystoreSigner) Address() common.Address {
+	return s.address
+}
+
+// SignMessage implements Signer
+func (s *KeystoreSigner) SignMessage(ctx context.Context, message []byte) (sig []byte, err error) {
+	err = s.withKey(func(key *ecdsa.PrivateKey) error {
+		sig, err = NewPrivateKeySigner(key).SignMessage(ctx, message)
+		return err
+	})
+	return sig, err
+}
+
+// SignTransaction implements TransactionSigner
+func (s *KeystoreSigner) SignTransaction(ctx context.Context, tx *types.Transaction, chainID *big.Int) (signed *types.Transaction, err error) {
+	err = s.withKey(func(key *ecdsa.PrivateKey) error {
+		signed, err = NewPrivateKeySigner(key).SignTransaction(ctx, tx, chainID)
+		return err
+	})
+	return signed, err
+}
+
+// Close zeroes the passphrase and the encrypted key held by the signer, after
+// which it can no longer sign. The copies of the passphrase that decrypting
+// makes are left to the garbage collector.
+func (s *KeystoreSigner) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.passphrase {
+		s.passphrase[i] = 0
+	}
+	for i := range s.keyJSON {
+		s.keyJSON[i] = 0
+	}
+	s.passphrase, s.keyJSON = nil, nil
+	return nil
+}
+
+// withKey decrypts the key, calls fn with it and zeroes it
+func (s *KeystoreSigner) withKey(fn func(key *ecdsa.PrivateKey) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keyJSON == nil {
+		return errKeystoreSignerClosed
+	}
+	key, err := keystore.DecryptKey(s.keyJSON, string(s.passphrase))
+	if err != nil {
+		if err == keystore.ErrDecrypt {
+			return fmt.Errorf("%w: %v", ErrInvalidParams, err)
+		}
+		return fmt.Errorf("%w: invalid keystore file: %v", ErrInvalidParams, err)
+	}
+	defer zeroKey(key.PrivateKey)
+	return fn(key.PrivateKey)
+}
+
+// zeroKey overwrites the private part of key
+func zeroKey(key *ecdsa.PrivateKey) {
+	b := key.D.Bits()
+	for i := range b {
+		b[i] = 0
+	}
+	key.D.SetUint64(0)
+}
@@ -0,0 +1,136 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// writeKeystoreFile encrypts key with passphrase into a keystore file in dir
+// with the light scrypt parameters, returning its path
+func writeKeystoreFile(t *testing.T, dir string, key *ecdsa.PrivateKey, passphrase string) string {
+	t.Helper()
+	keyJSON, err := keystore.EncryptKey(&keystore.Key{
+		Address:    crypto.PubkeyToAddress(key.PublicKey),
+		PrivateKey: key,
+	}, passphrase, keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("EncryptKey() error = %v", err)
+	}
+	path := filepath.Join(dir, "UTC--"+crypto.PubkeyToAddress(key.PublicKey).Hex())
+	if err := os.WriteFile(path, keyJSON, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestKeystoreSignerFromFile(t *testing.T) {
+	key, _ := crypto.HexToECDSA(signerTestKey[2:])
+	path := writeKeystoreFile(t, t.TempDir(), key, "correct horse")
+
+	signer, err := KeystoreSignerFromFile(path, "correct horse")
+	if err != nil {
+		t.Fatalf("KeystoreSignerFromFile() error = %v", err)
+	}
+	want, _ := PrivateKeySignerFromHex(signerTestKey)
+	if signer.Address() != want.Address() {
+		t.Errorf("Address() = %s, want %s", signer.Address(), want.Address())
+	}
+
+	sig, err := signer.SignMessage(context.Background(), []byte("hello"))
+	if err != nil {
+		t.Fatalf("SignMessage() error = %v", err)
+	}
+	sig[crypto.RecoveryIDOffset] -= 27
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte("hello")), sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != want.Address() {
+		t.Errorf("Expected the signature to recover to the signer, got %v, %v", pub, err)
+	}
+
+	if _, err := KeystoreSignerFromFile(path, "wrong"); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams for a wrong passphrase, got %v", err)
+	}
+	if _, err := KeystoreSignerFromFile(filepath.Join(t.TempDir(), "missing.json"), ""); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected os.ErrNotExist for a missing file, got %v", err)
+	}
+	if _, err := NewKeystoreSigner([]byte(`{}`), ""); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams for an invalid file, got %v", err)
+	}
+}
+
+func TestKeystoreSigner_Close(t *testing.T) {
+	key, _ := crypto.HexToECDSA(signerTestKey[2:])
+	signer, err := KeystoreSignerFromFile(writeKeystoreFile(t, t.TempDir(), key, "secret"), "secret")
+	if err != nil {
+		t.Fatalf("KeystoreSignerFromFile() error = %v", err)
+	}
+	passphrase, keyJSON := signer.passphrase, signer.keyJSON
+
+	if err := signer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	for _, b := range append(append([]byte(nil), passphrase...), keyJSON...) {
+		if b != 0 {
+			t.Fatal("Expected the passphrase and the encrypted key to be zeroed")
+		}
+	}
+	if _, err := signer.SignMessage(context.Background(), []byte("hello")); err == nil {
+		t.Error("Expected SignMessage() to fail after Close")
+	}
+}
+
+func TestKeystoreSignerFromDir(t *testing.T) {
+	dir := t.TempDir()
+	key, _ := crypto.HexToECDSA(signerTestKey[2:])
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	writeKeystoreFile(t, dir, key, "secret")
+	// Files that are not keys are skipped
+	os.WriteFile(filepath.Join(dir, "README"), []byte("keys"), 0o600)
+	os.WriteFile(filepath.Join(dir, ".backup"), []byte(`{"address":"0000000000000000000000000000000000000002"}`), 0o600)
+
+	signer, err := KeystoreSignerFromDir(dir, common.Address{}, "secret")
+	if err != nil {
+		t.Fatalf("KeystoreSignerFromDir() error = %v", err)
+	}
+	if signer.Address() != address {
+		t.Errorf("Address() = %s, want %s", signer.Address(), address)
+	}
+
+	other, _ := crypto.GenerateKey()
+	writeKeystoreFile(t, dir, other, "other")
+	// Only the file of the account is decrypted, so the other passphrase does not matter
+	if _, err := KeystoreSignerFromDir(dir, address, "secret"); err != nil {
+		t.Errorf("KeystoreSignerFromDir() by address error = %v", err)
+	}
+	if _, err := KeystoreSignerFromDir(dir, common.Address{}, "secret"); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams for an ambiguous directory, got %v", err)
+	}
+	if _, err := KeystoreSignerFromDir(dir, common.HexToAddress("0x01"), "secret"); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams for an unknown account, got %v", err)
+	}
+	if _, err := KeystoreSignerFromDir(filepath.Join(dir, "missing"), address, "secret"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected os.ErrNotExist for a missing directory, got %v", err)
+	}
+}
+
+func TestZeroKey(t *testing.T) {
+	key, _ := crypto.HexToECDSA(signerTestKey[2:])
+	words := key.D.Bits()
+	zeroKey(key)
+	if key.D.Sign() != 0 {
+		t.Errorf("Expected the key to be zero, got %x", key.D)
+	}
+	for _, w := range words {
+		if w != 0 {
+			t.Fatalf("Expected the key's memory to be overwritten, got %x", words)
+		}
+	}
+}
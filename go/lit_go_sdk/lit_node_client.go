@@ -2,96 +2,290 @@ package lit_go_sdk
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
 // LitNodeClient represents the main client for interacting with the Lit SDK
 type LitNodeClient struct {
-	port   int
-	server *NodeServer
+	port       int
+	socketPath string
+	server     *NodeServer
+	supervisor *supervisor
+	state      bridgeState
+	httpClient *http.Client
+	transport  Transport
+	secret     string
+	sessions   *SessionManager
+
+	signerMu sync.Mutex
+	signer   Signer
+
+	closeOnce sync.Once
+	closeErr  error
 }
 
-// NewLitNodeClient creates a new instance of LitNodeClient
-func NewLitNodeClient() (*LitNodeClient, error) {
-	port := 3092
+// NewLitNodeClient creates a new instance of LitNodeClient. Without options it
+// reuses a server already listening on port 3092 or starts a new one there.
+func NewLitNodeClient(opts ...Option) (*LitNodeClient, error) {
+	options := defaultClientOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	client := &LitNodeClient{
-		port: port,
+		port:       options.port,
+		httpClient: options.httpClient,
+		secret:     options.secret,
+	}
+	if options.autoSessionSigs {
+		sessions, err := NewSessionManager(client, options.sessionOptions...)
+		if err != nil {
+			return nil, err
+		}
+		client.sessions = sessions
+	}
+
+	if options.wrapTransport != nil {
+		var base Transport = httpTransport{client}
+		if options.transport != nil {
+			base = options.transport
+		}
+		client.transport = options.wrapTransport(base)
+	}
+	if options.transport != nil {
+		// The transport decides where requests go, there is no server to manage
+		if client.transport == nil {
+			client.transport = options.transport
+		}
+		return client, nil
 	}
 
-	// Check if server is already running
-	if !client.isServerRunning() {
-		server := NewNodeServer(port)
+	if options.unixSocket {
+		socketPath, err := newUnixSocketPath()
+		if err != nil {
+			return nil, err
+		}
+		httpClient, err := unixSocketHTTPClient(options.httpClient, socketPath)
+		if err != nil {
+			os.RemoveAll(filepath.Dir(socketPath))
+			return nil, err
+		}
+		client.port = 0
+		client.socketPath = socketPath
+		client.httpClient = httpClient
+	} else if client.port == 0 {
+		var err error
+		if client.port, err = freePort(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Check if server is already running. A freshly picked ephemeral port or
+	// a new socket never has one.
+	startServer := options.unixSocket || options.port == 0
+	if !startServer {
+		running, err := client.probeServer(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		startServer = !running
+	}
+	if !startServer {
+		// Only reuse a server that proves it is a bridge this client can talk to
+		ctx, cancel := context.WithTimeout(context.Background(), options.startupTimeout)
+		mismatch := client.verifyBridge(ctx)
+		cancel()
+		if mismatch != nil {
+			if !options.restartMismatchedBridge {
+				return nil, mismatch
+			}
+			if err := client.shutdownBridge(options.startupTimeout); err != nil {
+				return nil, fmt.Errorf("%v: %w", mismatch, err)
+			}
+			startServer = true
+		}
+	}
+
+	if startServer {
+		if client.secret == "" {
+			secret, err := newBridgeSecret()
+			if err != nil {
+				return nil, err
+			}
+			client.secret = secret
+		}
+
+		server := NewNodeServer(client.port)
+		server.secret = client.secret
+		server.socketPath = client.socketPath
+		server.nodePath = options.nodePath
+		server.serverPath = options.serverPath
+		server.cacheDir = options.cacheDir
+		server.env = options.env
+		server.dir = options.dir
+		server.gracePeriod = options.gracePeriod
+		if options.onServerExit != nil {
+			server.OnExit(options.onServerExit)
+		}
+		// The supervisor hooks into the server before it starts, so a crash
+		// right after the start is not missed
+		if options.autoRestart {
+			client.supervisor = newSupervisor(client, server, options)
+		}
 		if err := server.Start(); err != nil {
+			client.removeSocket()
 			return nil, fmt.Errorf("failed to start server: %w", err)
 		}
 		client.server = server
 
-		if err := client.waitForServer(10 * time.Second); err != nil {
+		if err := client.waitForServer(options.startupTimeout); err != nil {
 			server.Stop()
+			client.removeSocket()
 			return nil, err
 		}
+
+		if client.supervisor != nil {
+			go client.supervisor.run()
+		}
 	}
 
 	return client, nil
 }
 
+// url returns the URL of an endpoint on the server
+func (c *LitNodeClient) url(endpoint string) string {
+	if c.socketPath != "" {
+		return unixSocketBaseURL + endpoint
+	}
+	return fmt.Sprintf("http://localhost:%d%s", c.port, endpoint)
+}
+
+// removeSocket removes the private directory holding the client's Unix socket, if any
+func (c *LitNodeClient) removeSocket() {
+	if c.socketPath != "" {
+		os.RemoveAll(filepath.Dir(c.socketPath))
+	}
+}
+
 // isServerRunning checks if the Node.js server is already running
-func (c *LitNodeClient) isServerRunning() bool {
-	resp, err := http.Post(fmt.Sprintf("http://localhost:%d/isReady", c.port), "application/json", nil)
+func (c *LitNodeClient) isServerRunning(ctx context.Context) bool {
+	running, _ := c.probeServer(ctx)
+	return running
+}
+
+// probeServer is like isServerRunning, but reports a server that refuses the
+// client's bridge secret as a *BridgeMismatchError matching ErrUnauthorized.
+// Such a server holds the port, so starting another one would only time out.
+func (c *LitNodeClient) probeServer(ctx context.Context) (bool, error) {
+	statusCode, respBody, err := c.roundTrip(ctx, "/isReady", nil)
 	if err != nil {
-		return false
+		return false, nil
+	}
+	if statusCode == http.StatusUnauthorized {
+		return false, &BridgeMismatchError{
+			Reason: "the server already running refused the bridge secret, pass its secret with WithBridgeSecret or use another port",
+			Err:    &LitError{StatusCode: statusCode, Endpoint: "/isReady", Message: strings.TrimSpace(string(respBody))},
+		}
 	}
-	defer resp.Body.Close()
 
 	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return false
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return false, nil
 	}
-
-	// fmt.Println("result from isReady:", result)
-
-	return result["ready"] == true
+	return result["ready"] == true, nil
 }
 
 // waitForServer waits for the server to become available
 func (c *LitNodeClient) waitForServer(timeout time.Duration) error {
-	deadline := time.Now().Add(timeout)
-	for time.Now().Before(deadline) {
-		if c.isServerRunning() {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if c.isServerRunning(ctx) {
 			return nil
 		}
-		time.Sleep(100 * time.Millisecond)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("server failed to start within timeout period")
+		case <-ticker.C:
+		}
 	}
-	return fmt.Errorf("server failed to start within timeout period")
 }
 
-// SetAuthToken sets the auth token on the Node.js server
+// SetAuthToken sets the wallet the server authenticates with, given its hex
+// encoded private key. The key stays in this process: it is equivalent to
+// SetSigner with a PrivateKeySigner.
 func (c *LitNodeClient) SetAuthToken(authToken string) (map[string]interface{}, error) {
-	payload := map[string]string{"authToken": authToken}
-	return c.post("/setAuthToken", payload)
+	return c.SetAuthTokenContext(context.Background(), authToken)
 }
 
-func (c *LitNodeClient) PrintLast50LogLines() {
-	logs := c.server.GetLogs()
+// SetAuthTokenContext is like SetAuthToken but uses ctx for the request to the server
+func (c *LitNodeClient) SetAuthTokenContext(ctx context.Context, authToken string) (map[string]interface{}, error) {
+	signer, err := PrivateKeySignerFromHex(authToken)
+	if err != nil {
+		return nil, err
+	}
+	return c.SetSignerContext(ctx, signer)
+}
 
+func (c *LitNodeClient) PrintLast50LogLines() {
+	if c.server == nil {
+		// The server was already running, so its logs are not ours to print
+		return
+	}
 	fmt.Println("=== Start JS SDK Server Logs ===")
-	// Get all logs and split into lines
-	logLines := strings.Split(logs, "\n")
-	// Get last 50 lines (or all if less than 50)
-	start := len(logLines)
-	if start > 50 {
-		start = len(logLines) - 50
-	}
-	fmt.Println(strings.Join(logLines[start:], "\n"))
+	fmt.Println(strings.Join(c.server.lastLogLines(50), "\n"))
 	fmt.Println("=== End JS SDK Server Logs ===")
 }
 
-// post is a helper function to make POST requests
-func (c *LitNodeClient) post(endpoint string, payload interface{}) (map[string]interface{}, error) {
+// post is a helper function to make POST requests. The request is bound to
+// ctx, so cancelling it or hitting its deadline aborts the call to the server.
+// While a crashed server is being restarted, post waits for it to come back.
+func (c *LitNodeClient) post(ctx context.Context, endpoint string, payload interface{}) (map[string]interface{}, error) {
+	if c.supervisor != nil {
+		if err := c.supervisor.awaitReady(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := c.send(ctx, endpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+	c.state.record(endpoint, payload)
+	return result, nil
+}
+
+// setHeaders sets the content type and the shared secret on a request to the server
+func (c *LitNodeClient) setHeaders(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	if c.secret != "" {
+		req.Header.Set("Authorization", "Bearer "+c.secret)
+	}
+}
+
+// send makes a POST request to the server, answering the signature requests
+// the server makes before it responds
+func (c *LitNodeClient) send(ctx context.Context, endpoint string, payload interface{}) (map[string]interface{}, error) {
+	result, err := c.sendRequest(ctx, endpoint, payload)
+	if err != nil {
+		return nil, err
+	}
+	return c.answerSignatureRequests(ctx, endpoint, result)
+}
+
+// sendRequest makes a single POST request to the server
+func (c *LitNodeClient) sendRequest(ctx context.Context, endpoint string, payload interface{}) (map[string]interface{}, error) {
 	var body bytes.Buffer
 	if payload != nil {
 		if err := json.NewEncoder(&body).Encode(payload); err != nil {
@@ -99,173 +293,354 @@ func (c *LitNodeClient) post(endpoint string, payload interface{}) (map[string]i
 		}
 	}
 
-	resp, err := http.Post(
-		fmt.Sprintf("http://localhost:%d%s", c.port, endpoint),
-		"application/json",
-		&body,
-	)
+	statusCode, respBody, err := c.roundTrip(ctx, endpoint, body.Bytes())
 	if err != nil {
-		c.PrintLast50LogLines()
+		// A cancelled or expired context is the caller's doing, not a server problem
+		if ctx.Err() == nil {
+			c.PrintLast50LogLines()
+		}
 		return nil, err
 	}
-	defer resp.Body.Close()
 
 	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		if statusCode >= http.StatusBadRequest {
+			// Not a JSON error body, e.g. a proxy or express default error page
+			return nil, &LitError{
+				StatusCode: statusCode,
+				Endpoint:   endpoint,
+				Message:    strings.TrimSpace(string(respBody)),
+			}
+		}
 		c.PrintLast50LogLines()
 		return nil, err
 	}
+
+	if litErr := newLitError(endpoint, statusCode, result); litErr != nil {
+		return nil, litErr
+	}
 	return result, nil
 }
 
-// Close stops the Node.js server if it was started by this client
-func (c *LitNodeClient) Close() error {
-	if c.server != nil {
-		return c.server.Stop()
+// decodeResult decodes a server response into the typed result v
+func decodeResult(result map[string]interface{}, v interface{}) error {
+	// Round trip through JSON to decode the generic result
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return err
 	}
-	return nil
+	return json.Unmarshal(raw, v)
 }
 
-// LitNodeClientConfig represents the configuration for creating a new LitNodeClient instance
-type LitNodeClientConfig struct {
-	LitNetwork string `json:"litNetwork"`
-	Debug      bool   `json:"debug"`
+// Server returns the Node.js server started by the client, or nil if the
+// client is using a server that was already running
+func (c *LitNodeClient) Server() *NodeServer {
+	return c.server
+}
+
+// Close stops the Node.js server if it was started by this client. It first
+// disconnects from the Lit network, then shuts the server down gracefully and
+// removes its Unix socket, if any.
+// Close is safe to call more than once and from several goroutines; every
+// call returns the result of the first.
+func (c *LitNodeClient) Close() error {
+	c.closeOnce.Do(func() {
+		if c.supervisor != nil {
+			c.supervisor.close()
+		}
+		if c.server == nil {
+			return
+		}
+
+		select {
+		case <-c.server.Done():
+			// Nothing to disconnect, the process has already exited
+		default:
+			ctx, cancel := context.WithTimeout(context.Background(), c.server.gracePeriod)
+			defer cancel()
+			// Best effort: the server is going away whether or not this succeeds
+			c.send(ctx, "/litNodeClient/disconnect", nil)
+		}
+
+		c.closeErr = c.server.Stop()
+		c.removeSocket()
+	})
+	return c.closeErr
 }
 
-// ExecuteJsParams represents the parameters for executing JS code
-type ExecuteJsParams struct {
-	Code        string                 `json:"code"`
-	JsParams    map[string]interface{} `json:"jsParams"`
-	SessionSigs map[string]interface{} `json:"sessionSigs"`
+// LitNodeClientConfig represents the configuration for creating a new LitNodeClient instance
+type LitNodeClientConfig struct {
+	LitNetwork LitNetwork `json:"litNetwork"`
+	Debug      bool       `json:"debug"`
 }
 
 // PKPSignParams represents the parameters for signing with a PKP
 type PKPSignParams struct {
-	PubKey      string                 `json:"pubKey"`
-	ToSign      []int                  `json:"toSign"`
-	SessionSigs map[string]interface{} `json:"sessionSigs"`
+	PubKey      string      `json:"pubKey"`
+	ToSign      []int       `json:"toSign"`
+	SessionSigs SessionSigs `json:"sessionSigs"`
 }
 
 // SessionSigsParams represents the parameters for getting session signatures
 type SessionSigsParams struct {
-	Chain                   string        `json:"chain"`
-	Expiration              string        `json:"expiration"`
-	ResourceAbilityRequests []interface{} `json:"resourceAbilityRequests"`
+	Chain                   Chain                    `json:"chain"`
+	Expiration              string                   `json:"expiration"`
+	ResourceAbilityRequests []ResourceAbilityRequest `json:"resourceAbilityRequests"`
 }
 
 // New initializes a new LitNodeClient instance on the server
 func (c *LitNodeClient) New(config LitNodeClientConfig) (map[string]interface{}, error) {
-	return c.post("/litNodeClient/new", config)
+	return c.NewContext(context.Background(), config)
+}
+
+// NewContext is like New but uses ctx for the request to the server
+func (c *LitNodeClient) NewContext(ctx context.Context, config LitNodeClientConfig) (map[string]interface{}, error) {
+	return c.post(ctx, "/litNodeClient/new", config)
 }
 
 // Connect connects to the Lit network
 func (c *LitNodeClient) Connect() (map[string]interface{}, error) {
-	return c.post("/litNodeClient/connect", nil)
+	return c.ConnectContext(context.Background())
+}
+
+// ConnectContext is like Connect but uses ctx for the request to the server
+func (c *LitNodeClient) ConnectContext(ctx context.Context) (map[string]interface{}, error) {
+	return c.post(ctx, "/litNodeClient/connect", nil)
 }
 
 // GetProperty gets a property from the LitNodeClient
 func (c *LitNodeClient) GetProperty(property string) (map[string]interface{}, error) {
-	return c.post("/litNodeClient/getProperty", map[string]string{"property": property})
+	return c.GetPropertyContext(context.Background(), property)
+}
+
+// GetPropertyContext is like GetProperty but uses ctx for the request to the server
+func (c *LitNodeClient) GetPropertyContext(ctx context.Context, property string) (map[string]interface{}, error) {
+	return c.post(ctx, "/litNodeClient/getProperty", map[string]string{"property": property})
 }
 
-// ExecuteJs executes JavaScript code on the Lit network
-func (c *LitNodeClient) ExecuteJs(params ExecuteJsParams) (map[string]interface{}, error) {
-	return c.post("/litNodeClient/executeJs", params)
+// ExecuteJs executes a Lit Action on the Lit network, given its code or the
+// IPFS CID it was deployed to
+func (c *LitNodeClient) ExecuteJs(params ExecuteJsParams) (*ExecuteJsResult, error) {
+	return c.ExecuteJsContext(context.Background(), params)
+}
+
+// ExecuteJsContext is like ExecuteJs but uses ctx for the request to the server
+func (c *LitNodeClient) ExecuteJsContext(ctx context.Context, params ExecuteJsParams) (*ExecuteJsResult, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if params.ResponseStrategy == nil {
+		params.ResponseStrategy = &ResponseStrategy{Strategy: ResponseStrategyMostCommon}
+	}
+	result, err := c.postWithSessionSigs(ctx, "/litNodeClient/executeJs", &params, &params.SessionSigs, "", litActionExecutionRequest)
+	if err != nil {
+		return nil, err
+	}
+	var executeJsResult ExecuteJsResult
+	if err := decodeResult(result, &executeJsResult); err != nil {
+		return nil, fmt.Errorf("failed to decode executeJs result: %w", err)
+	}
+	if params.ResponseStrategy != nil {
+		if err := params.ResponseStrategy.filter(&executeJsResult); err != nil {
+			return nil, err
+		}
+	}
+	return &executeJsResult, nil
 }
 
 // GetSessionSigs gets session signatures
-func (c *LitNodeClient) GetSessionSigs(params SessionSigsParams) (map[string]interface{}, error) {
-	return c.post("/litNodeClient/getSessionSigs", params)
+func (c *LitNodeClient) GetSessionSigs(params SessionSigsParams) (SessionSigs, error) {
+	return c.GetSessionSigsContext(context.Background(), params)
+}
+
+// GetSessionSigsContext is like GetSessionSigs but uses ctx for the request to the server
+func (c *LitNodeClient) GetSessionSigsContext(ctx context.Context, params SessionSigsParams) (SessionSigs, error) {
+	if err := validateResourceAbilityRequests(params.ResourceAbilityRequests); err != nil {
+		return nil, err
+	}
+	result, err := c.post(ctx, "/litNodeClient/getSessionSigs", params)
+	if err != nil {
+		return nil, err
+	}
+	return SessionSigsFromResult(result)
 }
 
 // PKPSign signs data using a PKP
 func (c *LitNodeClient) PKPSign(params PKPSignParams) (map[string]interface{}, error) {
-	return c.post("/litNodeClient/pkpSign", params)
+	return c.PKPSignContext(context.Background(), params)
+}
+
+// PKPSignContext is like PKPSign but uses ctx for the request to the server
+func (c *LitNodeClient) PKPSignContext(ctx context.Context, params PKPSignParams) (map[string]interface{}, error) {
+	return c.postWithSessionSigs(ctx, "/litNodeClient/pkpSign", &params, &params.SessionSigs, "", pkpSigningRequest)
 }
 
 // Disconnect disconnects from the Lit network
 func (c *LitNodeClient) Disconnect() (map[string]interface{}, error) {
-	return c.post("/litNodeClient/disconnect", nil)
+	return c.DisconnectContext(context.Background())
+}
+
+// DisconnectContext is like Disconnect but uses ctx for the request to the server
+func (c *LitNodeClient) DisconnectContext(ctx context.Context) (map[string]interface{}, error) {
+	return c.post(ctx, "/litNodeClient/disconnect", nil)
 }
 
 // LitContractsClientConfig represents the configuration for creating a new LitContractsClient
 type LitContractsClientConfig struct {
-	PrivateKey string `json:"privateKey"`
-	Network    string `json:"network"`
-	Debug      bool   `json:"debug"`
+	// PrivateKey is the hex encoded key of the wallet paying for transactions.
+	// It is not sent to the server, which asks the client to sign instead.
+	// If empty, the signer set with SetSigner or SetAuthToken is used.
+	// Otherwise the key becomes the client's signer, like with SetAuthToken,
+	// and the session sigs cached for another wallet are dropped.
+	PrivateKey string     `json:"-"`
+	Network    LitNetwork `json:"network"`
+	Debug      bool       `json:"debug"`
+}
+
+// litContractsClientRequest is the request creating a LitContractsClient
+// for the wallet at Address
+type litContractsClientRequest struct {
+	LitContractsClientConfig
+	Address string `json:"address"`
 }
 
 // NewLitContractsClient initializes a new LitContractsClient
 func (c *LitNodeClient) NewLitContractsClient(config LitContractsClientConfig) (map[string]interface{}, error) {
-	return c.post("/litContractsClient/new", config)
+	return c.NewLitContractsClientContext(context.Background(), config)
+}
+
+// NewLitContractsClientContext is like NewLitContractsClient but uses ctx for the request to the server
+func (c *LitNodeClient) NewLitContractsClientContext(ctx context.Context, config LitContractsClientConfig) (map[string]interface{}, error) {
+	signer := c.currentSigner()
+	if config.PrivateKey != "" {
+		keySigner, err := PrivateKeySignerFromHex(config.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		signer = keySigner
+		config.PrivateKey = ""
+	}
+	if signer == nil {
+		return nil, ErrNoSigner
+	}
+	result, err := c.post(ctx, "/litContractsClient/new", litContractsClientRequest{config, signer.Address().Hex()})
+	if err != nil {
+		return nil, err
+	}
+	c.setSigner(signer)
+	return result, nil
 }
 
 // MintWithAuthParams represents the parameters for minting with auth
 type MintWithAuthParams struct {
-	AuthMethod map[string]interface{} `json:"authMethod"`
-	Scopes     []int                  `json:"scopes"`
+	// AuthMethod is the auth method allowed to use the PKP, e.g. from
+	// EthWalletAuthMethod or OAuthAuthMethod
+	AuthMethod AuthMethod `json:"authMethod"`
+	// Scopes are what the auth method may do with the PKP
+	Scopes []AuthMethodScope `json:"scopes"`
+	// Pubkey is the public key of the credential, required for WebAuthn
+	Pubkey string `json:"pubkey,omitempty"`
+}
+
+// Validate checks the auth method. The error wraps ErrInvalidParams.
+func (p MintWithAuthParams) Validate() error {
+	if err := p.AuthMethod.Validate(); err != nil {
+		return err
+	}
+	if p.AuthMethod.AuthMethodType == AuthMethodTypeWebAuthn && p.Pubkey == "" {
+		return fmt.Errorf("%w: minting with a WebAuthn auth method needs the credential's Pubkey", ErrInvalidParams)
+	}
+	return nil
 }
 
 // MintWithAuth mints a new PKP with authentication
 func (c *LitNodeClient) MintWithAuth(params MintWithAuthParams) (map[string]interface{}, error) {
-	// json stringify the AuthSig
-	authSig, ok := params.AuthMethod["accessToken"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("accessToken is not a map")
-	}
-	authSigJSON, err := json.Marshal(authSig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal accessToken: %w", err)
+	return c.MintWithAuthContext(context.Background(), params)
+}
+
+// MintWithAuthContext is like MintWithAuth but uses ctx for the request to the server
+func (c *LitNodeClient) MintWithAuthContext(ctx context.Context, params MintWithAuthParams) (map[string]interface{}, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
 	}
-	params.AuthMethod["accessToken"] = string(authSigJSON)
-	return c.post("/litContractsClient/mintWithAuth", params)
+	return c.post(ctx, "/litContractsClient/mintWithAuth", params)
 }
 
 // CreateSiweMessageParams represents the parameters for creating a SIWE message
 type CreateSiweMessageParams struct {
-	URI           string        `json:"uri"`
-	Expiration    string        `json:"expiration"`
-	Resources     []interface{} `json:"resources"`
-	WalletAddress string        `json:"walletAddress"`
+	URI           string                   `json:"uri"`
+	Expiration    string                   `json:"expiration"`
+	Resources     []ResourceAbilityRequest `json:"resources"`
+	WalletAddress string                   `json:"walletAddress"`
 }
 
 // CreateSiweMessage creates a SIWE message
 func (c *LitNodeClient) CreateSiweMessage(params CreateSiweMessageParams) (map[string]interface{}, error) {
-	return c.post("/authHelpers/createSiweMessage", params)
+	return c.CreateSiweMessageContext(context.Background(), params)
 }
 
-// GenerateAuthSig generates an auth signature
-func (c *LitNodeClient) GenerateAuthSig(toSign string) (map[string]interface{}, error) {
-	return c.post("/authHelpers/generateAuthSig", map[string]string{"toSign": toSign})
+// CreateSiweMessageContext is like CreateSiweMessage but uses ctx for the request to the server
+func (c *LitNodeClient) CreateSiweMessageContext(ctx context.Context, params CreateSiweMessageParams) (map[string]interface{}, error) {
+	if err := validateResourceAbilityRequests(params.Resources); err != nil {
+		return nil, err
+	}
+	return c.post(ctx, "/authHelpers/createSiweMessage", params)
+}
+
+// GenerateAuthSig signs toSign with the Signer set with SetSigner or
+// SetAuthToken, like NewAuthSig. Nothing is sent to the server.
+func (c *LitNodeClient) GenerateAuthSig(toSign string) (*AuthSig, error) {
+	return c.GenerateAuthSigContext(context.Background(), toSign)
+}
+
+// GenerateAuthSigContext is like GenerateAuthSig but passes ctx to the signer
+func (c *LitNodeClient) GenerateAuthSigContext(ctx context.Context, toSign string) (*AuthSig, error) {
+	return NewAuthSig(ctx, c.currentSigner(), toSign)
 }
 
 // EncryptStringParams represents the parameters for encrypting a string
 type EncryptStringParams struct {
-	DataToEncrypt                  string        `json:"dataToEncrypt"`
-	AccessControlConditions        []interface{} `json:"accessControlConditions,omitempty"`
-	EvmContractConditions          []interface{} `json:"evmContractConditions,omitempty"`
-	SolRpcConditions               []interface{} `json:"solRpcConditions,omitempty"`
-	UnifiedAccessControlConditions []interface{} `json:"unifiedAccessControlConditions,omitempty"`
+	DataToEncrypt                  string            `json:"dataToEncrypt"`
+	AccessControlConditions        Conditions        `json:"accessControlConditions,omitempty"`
+	EvmContractConditions          Conditions        `json:"evmContractConditions,omitempty"`
+	SolRpcConditions               Conditions        `json:"solRpcConditions,omitempty"`
+	UnifiedAccessControlConditions UnifiedConditions `json:"unifiedAccessControlConditions,omitempty"`
 }
 
 // DecryptStringParams represents the parameters for decrypting a string
 type DecryptStringParams struct {
-	Ciphertext                     string                 `json:"ciphertext"`
-	DataToEncryptHash              string                 `json:"dataToEncryptHash"`
-	AccessControlConditions        []interface{}          `json:"accessControlConditions,omitempty"`
-	EvmContractConditions          []interface{}          `json:"evmContractConditions,omitempty"`
-	SolRpcConditions               []interface{}          `json:"solRpcConditions,omitempty"`
-	UnifiedAccessControlConditions []interface{}          `json:"unifiedAccessControlConditions,omitempty"`
-	SessionSigs                    map[string]interface{} `json:"sessionSigs"`
-	Chain                          string                 `json:"chain"`
+	Ciphertext                     string            `json:"ciphertext"`
+	DataToEncryptHash              string            `json:"dataToEncryptHash"`
+	AccessControlConditions        Conditions        `json:"accessControlConditions,omitempty"`
+	EvmContractConditions          Conditions        `json:"evmContractConditions,omitempty"`
+	SolRpcConditions               Conditions        `json:"solRpcConditions,omitempty"`
+	UnifiedAccessControlConditions UnifiedConditions `json:"unifiedAccessControlConditions,omitempty"`
+	SessionSigs                    SessionSigs       `json:"sessionSigs"`
+	Chain                          Chain             `json:"chain"`
 }
 
 // EncryptString encrypts a string using Lit Protocol
 func (c *LitNodeClient) EncryptString(params EncryptStringParams) (map[string]interface{}, error) {
-	return c.post("/litNodeClient/encryptString", params)
+	return c.EncryptStringContext(context.Background(), params)
+}
+
+// EncryptStringContext is like EncryptString but uses ctx for the request to the server
+func (c *LitNodeClient) EncryptStringContext(ctx context.Context, params EncryptStringParams) (map[string]interface{}, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return c.post(ctx, "/litNodeClient/encryptString", params)
 }
 
 // DecryptString decrypts a string using Lit Protocol
 func (c *LitNodeClient) DecryptString(params DecryptStringParams) (map[string]interface{}, error) {
-	return c.post("/litNodeClient/decryptString", params)
+	return c.DecryptStringContext(context.Background(), params)
+}
+
+// DecryptStringContext is like DecryptString but uses ctx for the request to the server
+func (c *LitNodeClient) DecryptStringContext(ctx context.Context, params DecryptStringParams) (map[string]interface{}, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return c.postWithSessionSigs(ctx, "/litNodeClient/decryptString", &params, &params.SessionSigs, params.Chain, accessControlConditionDecryptionRequest)
 }
@@ -0,0 +1,84 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client wired to a local HTTP server running handler
+func newTestClient(t *testing.T, handler http.Handler) *LitNodeClient {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	_, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to parse test server address: %v", err)
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatalf("failed to parse test server port: %v", err)
+	}
+	return &LitNodeClient{port: portNum, httpClient: http.DefaultClient}
+}
+
+func TestExecuteJsContext_Cancelled(t *testing.T) {
+	received := make(chan struct{})
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		close(received)
+		<-r.Context().Done()
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-received
+		cancel()
+	}()
+
+	_, err := client.ExecuteJsContext(ctx, ExecuteJsParams{Code: "(async () => {})()"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("ExecuteJsContext() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestPKPSignContext_Deadline(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.PKPSignContext(ctx, PKPSignParams{PubKey: "0x04"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("PKPSignContext() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestConnectContext_Success(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/litNodeClient/connect" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success":true}`))
+	}))
+
+	result, err := client.ConnectContext(context.Background())
+	if err != nil {
+		t.Fatalf("ConnectContext() error = %v", err)
+	}
+	if result["success"] != true {
+		t.Errorf("Expected success to be true, got %v", result["success"])
+	}
+}
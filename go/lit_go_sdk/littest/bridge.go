@@ -0,0 +1,763 @@
+// Package littest provides an in-process fake of the JS SDK server, so code
+// built on lit_go_sdk can be unit tested without Node.js, a funded wallet or
+// the Lit network.
+//
+//	bridge := littest.NewBridge()
+//	client, err := bridge.NewClient()
+//
+// The fake implements every endpoint of the real server with deterministic
+// responses: the same requests in the same order always get the same answers.
+// It keeps the state the real server keeps, so calls that need an auth token
+// or a LitNodeClient fail the same way, and asks the client's Signer for
+// signatures like the real server does. Endpoints can be scripted with Handle.
+//
+// Code that depends on the lit_go_sdk.LitClient interface, or one of the
+// smaller interfaces it is made of, can instead be tested with a Mock, which
+// records calls and answers with scripted responses without any bridge.
+package littest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	lit "github.com/lit-protocol/lit-polyglot-sdk/go/lit_go_sdk"
+)
+
+// defaultNetwork is the network the real server connects to on startup
+const defaultNetwork = "datil-dev"
+
+// yellowstoneChainID is the chain ID of Chronicle Yellowstone, where the Lit
+// contracts live
+var yellowstoneChainID = big.NewInt(175188)
+
+// pkpNFTAddress is the address mint transactions are sent to
+var pkpNFTAddress = common.HexToAddress("0x487A9D096BB4B7Ac1520Cb12370e31e677B175EA")
+
+// HandlerFunc answers a request to an endpoint. body is the decoded JSON
+// payload, or nil for a request without one. The returned value is encoded as
+// the JSON response. A returned error is reported the way the real server
+// reports a thrown error; return an *Error to choose the status and Lit code.
+type HandlerFunc func(body map[string]interface{}) (interface{}, error)
+
+// Error is an error response from a Bridge
+type Error struct {
+	// StatusCode is the HTTP status code, 500 if zero
+	StatusCode int
+	// Message is the error message
+	Message string
+	// Code is the Lit error code, e.g. InvalidSessionSigs
+	Code string
+	// Kind is the Lit error kind, e.g. Validation
+	Kind string
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Call is a request received by a Bridge
+type Call struct {
+	// Endpoint is the endpoint called, e.g. /litNodeClient/executeJs
+	Endpoint string
+	// Body is the JSON payload of the request, empty if there was none
+	Body json.RawMessage
+}
+
+// Bridge is a fake JS SDK server. It implements lit_go_sdk.Transport, to be
+// used with lit_go_sdk.WithTransport, and http.Handler, to be served with
+// net/http/httptest. It is safe for concurrent use.
+type Bridge struct {
+	mu        sync.Mutex
+	network   string
+	connected bool
+	// wallet is the key set with an auth token, signer the address of the
+	// client's Signer. At most one of them is set.
+	wallet    *ecdsa.PrivateKey
+	signer    *common.Address
+	contracts bool
+	minted    int
+	calls     []Call
+	handlers  map[string]HandlerFunc
+
+	signatureRequests int
+	pending           map[string]func(body map[string]interface{}) (interface{}, error)
+}
+
+// NewBridge returns a fake JS SDK server in the state the real server starts
+// in: connected to datil-dev, without an auth token.
+func NewBridge() *Bridge {
+	b := &Bridge{
+		network:   defaultNetwork,
+		connected: true,
+	}
+	b.handlers = map[string]HandlerFunc{
+		"/isReady":                         b.isReady,
+		"/handshake":                       b.handshake,
+		"/shutdown":                        b.shutdown,
+		"/setAuthToken":                    b.setAuthToken,
+		"/setSigner":                       b.setSigner,
+		"/signer/respond":                  b.respondToSignatureRequest,
+		"/litNodeClient/new":               b.newLitNodeClient,
+		"/litNodeClient/connect":           b.connect,
+		"/litNodeClient/disconnect":        b.disconnect,
+		"/litNodeClient/getProperty":       b.getProperty,
+		"/litNodeClient/getSessionSigs":    b.getSessionSigs,
+		"/litNodeClient/executeJs":         b.executeJs,
+		"/litNodeClient/pkpSign":           b.pkpSign,
+		"/litNodeClient/encryptString":     b.encryptString,
+		"/litNodeClient/decryptString":     b.decryptString,
+		"/litContractsClient/new":          b.newLitContractsClient,
+		"/litContractsClient/mintWithAuth": b.mintWithAuth,
+		"/authHelpers/createSiweMessage":   b.createSiweMessage,
+	}
+	return b
+}
+
+// NewClient returns a LitNodeClient that sends its requests to the bridge
+func (b *Bridge) NewClient(opts ...lit.Option) (*lit.LitNodeClient, error) {
+	return lit.NewLitNodeClient(append(opts, lit.WithTransport(b))...)
+}
+
+// Handle replaces the handler of endpoint, e.g. to script a response or a
+// failure. A nil fn removes the endpoint, which then answers 404.
+func (b *Bridge) Handle(endpoint string, fn HandlerFunc) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if fn == nil {
+		delete(b.handlers, endpoint)
+		return
+	}
+	b.handlers[endpoint] = fn
+}
+
+// Calls returns the requests received so far, in order
+func (b *Bridge) Calls() []Call {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Call(nil), b.calls...)
+}
+
+// Post implements lit_go_sdk.Transport
+func (b *Bridge) Post(ctx context.Context, endpoint string, body []byte) (int, []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	b.mu.Lock()
+	b.calls = append(b.calls, Call{Endpoint: endpoint, Body: append(json.RawMessage(nil), body...)})
+	handler := b.handlers[endpoint]
+	b.mu.Unlock()
+
+	if handler == nil {
+		// Like express when no route matches
+		return http.StatusNotFound, []byte("Cannot POST " + endpoint), nil
+	}
+
+	var payload map[string]interface{}
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &payload); err != nil {
+			return encodeError(&Error{StatusCode: http.StatusBadRequest, Message: err.Error()})
+		}
+	}
+
+	result, err := handler(payload)
+	if err != nil {
+		return encodeError(err)
+	}
+	respBody, err := json.Marshal(result)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, respBody, nil
+}
+
+// ServeHTTP implements http.Handler
+func (b *Bridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Cannot "+r.Method+" "+r.URL.Path, http.StatusNotFound)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	statusCode, respBody, err := b.Post(r.Context(), r.URL.Path, body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(respBody)
+}
+
+// encodeError encodes err the way the real server does: precondition
+// failures as {"success": false, "error": "..."} and thrown errors through
+// its error-handling middleware
+func encodeError(err error) (int, []byte, error) {
+	e, ok := err.(*Error)
+	if !ok {
+		e = &Error{Message: err.Error()}
+	}
+	statusCode := e.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusInternalServerError
+	}
+
+	var body interface{}
+	if e.Code == "" && e.Kind == "" && statusCode == http.StatusBadRequest {
+		body = map[string]interface{}{"success": false, "error": e.Message}
+	} else {
+		details := map[string]interface{}{"message": e.Message}
+		if e.Code != "" {
+			details["code"] = e.Code
+		}
+		if e.Kind != "" {
+			details["kind"] = e.Kind
+		}
+		body = map[string]interface{}{"error": details}
+	}
+	respBody, marshalErr := json.Marshal(body)
+	if marshalErr != nil {
+		return 0, nil, marshalErr
+	}
+	return statusCode, respBody, nil
+}
+
+// badRequest is the error the real server returns when a precondition fails
+func badRequest(message string) error {
+	return &Error{StatusCode: http.StatusBadRequest, Message: message}
+}
+
+// invalidArgument is the error the JS SDK throws for missing or bad parameters
+func invalidArgument(format string, args ...interface{}) error {
+	return &Error{Message: fmt.Sprintf(format, args...), Code: "InvalidArgumentException", Kind: "Validation"}
+}
+
+// errWalletNotInitialized is returned by calls that need an auth token before one is set
+var errWalletNotInitialized = badRequest("Ethers wallet not initialized - Please set a Lit auth token.")
+
+func (b *Bridge) isReady(map[string]interface{}) (interface{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return map[string]interface{}{"ready": b.connected}, nil
+}
+
+func (b *Bridge) handshake(map[string]interface{}) (interface{}, error) {
+	return lit.BridgeInfo{
+		BridgeVersion: lit.BridgeVersion,
+		InstanceID:    "littest",
+		PID:           os.Getpid(),
+		UID:           os.Getuid(),
+	}, nil
+}
+
+func (b *Bridge) shutdown(map[string]interface{}) (interface{}, error) {
+	return success(nil), nil
+}
+
+func (b *Bridge) setAuthToken(body map[string]interface{}) (interface{}, error) {
+	key, err := parsePrivateKey(stringField(body, "authToken"))
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.wallet, b.signer = key, nil
+	b.contracts = true
+	return success(nil), nil
+}
+
+func (b *Bridge) setSigner(body map[string]interface{}) (interface{}, error) {
+	address, err := parseAddress(stringField(body, "address"))
+	if err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.wallet, b.signer = nil, &address
+	b.contracts = true
+	return success(nil), nil
+}
+
+// signMessage signs message with the wallet and calls then with the
+// signature. With the client's Signer, it returns a signature request instead
+// and calls then once the client has answered it with a valid signature.
+func (b *Bridge) signMessage(message string, then func(sig []byte) (interface{}, error)) (interface{}, error) {
+	b.mu.Lock()
+	wallet, signer := b.wallet, b.signer
+	b.mu.Unlock()
+
+	if wallet != nil {
+		sig, err := crypto.Sign(textHash(message), wallet)
+		if err != nil {
+			return nil, err
+		}
+		sig[crypto.RecoveryIDOffset] += 27
+		return then(sig)
+	}
+	return b.requestSignature(map[string]interface{}{"message": hexutil.Encode([]byte(message))}, func(body map[string]interface{}) (interface{}, error) {
+		sig, err := hexutil.Decode(stringField(body, "messageSignature"))
+		if err != nil || len(sig) != crypto.SignatureLength || sig[crypto.RecoveryIDOffset] < 27 {
+			return nil, invalidArgument("invalid message signature")
+		}
+		recoverable := append([]byte(nil), sig...)
+		recoverable[crypto.RecoveryIDOffset] -= 27
+		pub, err := crypto.SigToPub(textHash(message), recoverable)
+		if err != nil || crypto.PubkeyToAddress(*pub) != *signer {
+			return nil, invalidArgument("message signature is not from the signer")
+		}
+		return then(sig)
+	})
+}
+
+// requestSignature returns a signature request for the client's Signer, and
+// has then answer the client's response to it
+func (b *Bridge) requestSignature(fields map[string]interface{}, then func(body map[string]interface{}) (interface{}, error)) (interface{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.signatureRequests++
+	id := digest("littest signature request", fmt.Sprint(b.signatureRequests))
+	if b.pending == nil {
+		b.pending = make(map[string]func(map[string]interface{}) (interface{}, error))
+	}
+	b.pending[id] = then
+
+	request := map[string]interface{}{"id": id}
+	for k, v := range fields {
+		request[k] = v
+	}
+	return map[string]interface{}{"signatureRequest": request}, nil
+}
+
+func (b *Bridge) respondToSignatureRequest(body map[string]interface{}) (interface{}, error) {
+	id := stringField(body, "id")
+	b.mu.Lock()
+	then := b.pending[id]
+	delete(b.pending, id)
+	b.mu.Unlock()
+
+	if then == nil {
+		return nil, badRequest("Unknown or expired signature request")
+	}
+	if message := stringField(body, "error"); message != "" {
+		return nil, &Error{Message: "SDK signer failed: " + message}
+	}
+	return then(body)
+}
+
+func (b *Bridge) newLitNodeClient(body map[string]interface{}) (interface{}, error) {
+	network := stringField(body, "litNetwork")
+	if network == "" {
+		network = defaultNetwork
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.network = network
+	b.connected = true
+	return success(nil), nil
+}
+
+func (b *Bridge) connect(map[string]interface{}) (interface{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.connected = true
+	return success(nil), nil
+}
+
+func (b *Bridge) disconnect(map[string]interface{}) (interface{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.connected = false
+	return success(nil), nil
+}
+
+func (b *Bridge) getProperty(body map[string]interface{}) (interface{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	properties := map[string]interface{}{
+		"ready":          b.connected,
+		"config":         map[string]interface{}{"litNetwork": b.network},
+		"connectedNodes": nodeURLs,
+	}
+	// Properties the LitNodeClient does not have are undefined, so the key is left out
+	result := success(nil)
+	if value, ok := properties[stringField(body, "property")]; ok {
+		result["property"] = value
+	}
+	return result, nil
+}
+
+// nodeURLs are the nodes the fake network consists of
+var nodeURLs = []string{
+	"https://node-1.littest.invalid:443",
+	"https://node-2.littest.invalid:443",
+	"https://node-3.littest.invalid:443",
+}
+
+func (b *Bridge) getSessionSigs(body map[string]interface{}) (interface{}, error) {
+	walletAddress := b.walletAddress()
+	if walletAddress == "" {
+		return nil, errWalletNotInitialized
+	}
+
+	// A session key derived from the wallet and the request, so identical
+	// requests get identical session sigs
+	request, _ := json.Marshal(body)
+	sessionKey := digest(walletAddress, string(request))
+
+	// The wallet signs the session key into an auth sig, like the
+	// authNeededCallback of the real server
+	message, err := siweMessage(walletAddress, "lit:session:"+sessionKey, stringField(body, "expiration"), body["resourceAbilityRequests"])
+	if err != nil {
+		return nil, err
+	}
+	return b.signMessage(message, func([]byte) (interface{}, error) {
+		return sessionSigsResponse(body, sessionKey), nil
+	})
+}
+
+// sessionSigsResponse returns session sigs for sessionKey from every node
+func sessionSigsResponse(body map[string]interface{}, sessionKey string) map[string]interface{} {
+	sessionSigs := make(map[string]interface{}, len(nodeURLs))
+	for _, nodeURL := range nodeURLs {
+		signedMessage, _ := json.Marshal(map[string]interface{}{
+			"sessionKey":              sessionKey,
+			"resourceAbilityRequests": body["resourceAbilityRequests"],
+			"capabilities":            []interface{}{},
+			"issuedAt":                "2024-01-01T00:00:00.000Z",
+			"expiration":              body["expiration"],
+			"nodeAddress":             nodeURL,
+		})
+		sessionSigs[nodeURL] = map[string]interface{}{
+			"sig":           digest(nodeURL, string(signedMessage)) + digest(string(signedMessage), nodeURL),
+			"derivedVia":    "litSessionSignViaNacl",
+			"signedMessage": string(signedMessage),
+			"address":       sessionKey,
+			"algo":          "ed25519",
+		}
+	}
+	return success(map[string]interface{}{"sessionSigs": sessionSigs})
+}
+
+// walletAddress returns the address of the wallet or of the client's Signer,
+// or "" if neither is set
+func (b *Bridge) walletAddress() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch {
+	case b.wallet != nil:
+		return crypto.PubkeyToAddress(b.wallet.PublicKey).Hex()
+	case b.signer != nil:
+		return b.signer.Hex()
+	}
+	return ""
+}
+
+func (b *Bridge) executeJs(body map[string]interface{}) (interface{}, error) {
+	code, ipfsID := stringField(body, "code"), stringField(body, "ipfsId")
+	if (code == "") == (ipfsID == "") {
+		return nil, invalidArgument("exactly one of code or ipfsId is required")
+	}
+	if err := requireSessionSigs(body); err != nil {
+		return nil, err
+	}
+
+	var response interface{} = ""
+	if strategy, ok := body["responseStrategy"].(map[string]interface{}); ok {
+		switch strategy["strategy"] {
+		case "mostCommon", "leastCommon":
+		case "custom":
+			// Like the real server, return every node's response for the SDK
+			// to filter
+			responses := make([]string, len(nodeURLs))
+			data, _ := json.Marshal(responses)
+			response = string(data)
+		default:
+			return nil, badRequest(fmt.Sprintf("Unknown response strategy: %v", strategy["strategy"]))
+		}
+	}
+	return map[string]interface{}{
+		"success":     true,
+		"signatures":  map[string]interface{}{},
+		"decryptions": []interface{}{},
+		"claims":      map[string]interface{}{},
+		"response":    response,
+		"logs":        "",
+	}, nil
+}
+
+func (b *Bridge) pkpSign(body map[string]interface{}) (interface{}, error) {
+	pubKey := stringField(body, "pubKey")
+	if pubKey == "" {
+		return nil, invalidArgument("pubKey is required")
+	}
+	toSign, ok := body["toSign"].([]interface{})
+	if !ok || len(toSign) == 0 {
+		return nil, invalidArgument("toSign is required")
+	}
+	if err := requireSessionSigs(body); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, len(toSign))
+	for i, v := range toSign {
+		n, ok := v.(float64)
+		if !ok || n < 0 || n > 255 {
+			return nil, invalidArgument("toSign must be an array of bytes")
+		}
+		data[i] = byte(n)
+	}
+
+	// Not a valid signature by pubKey, but stable for the same input
+	r := digest(pubKey, hex.EncodeToString(data))
+	s := digest(r, pubKey)
+	return map[string]interface{}{
+		"signature": map[string]interface{}{
+			"r":          r,
+			"s":          s,
+			"recid":      0,
+			"signature":  "0x" + r + s + "1b",
+			"publicKey":  strings.TrimPrefix(pubKey, "0x"),
+			"dataSigned": hex.EncodeToString(data),
+		},
+	}, nil
+}
+
+func (b *Bridge) encryptString(body map[string]interface{}) (interface{}, error) {
+	if !hasConditions(body) {
+		return nil, invalidArgument("You must provide either accessControlConditions or evmContractConditions or solRpcConditions or unifiedAccessControlConditions")
+	}
+	data, ok := body["dataToEncrypt"].(string)
+	if !ok {
+		return nil, invalidArgument("dataToEncrypt is required")
+	}
+	sum := sha256.Sum256([]byte(data))
+	return map[string]interface{}{
+		"ciphertext":        base64.StdEncoding.EncodeToString([]byte(data)),
+		"dataToEncryptHash": hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+func (b *Bridge) decryptString(body map[string]interface{}) (interface{}, error) {
+	if !hasConditions(body) {
+		return nil, invalidArgument("You must provide either accessControlConditions or evmContractConditions or solRpcConditions or unifiedAccessControlConditions")
+	}
+	if body["authSig"] == nil {
+		if err := requireSessionSigs(body); err != nil {
+			return nil, err
+		}
+	}
+	data, err := base64.StdEncoding.DecodeString(stringField(body, "ciphertext"))
+	if err != nil {
+		return nil, invalidArgument("ciphertext is not valid base64")
+	}
+	sum := sha256.Sum256(data)
+	if stringField(body, "dataToEncryptHash") != hex.EncodeToString(sum[:]) {
+		return nil, &Error{Message: "decryption failed: dataToEncryptHash does not match the ciphertext", Code: "NodeError", Kind: "Unexpected"}
+	}
+	return map[string]interface{}{"decryptedString": string(data)}, nil
+}
+
+func (b *Bridge) newLitContractsClient(body map[string]interface{}) (interface{}, error) {
+	if stringField(body, "privateKey") == "" {
+		return b.setSigner(body)
+	}
+	return b.setAuthToken(map[string]interface{}{"authToken": body["privateKey"]})
+}
+
+func (b *Bridge) mintWithAuth(body map[string]interface{}) (interface{}, error) {
+	b.mu.Lock()
+	contracts, signer := b.contracts, b.signer
+	b.mu.Unlock()
+	if !contracts {
+		return nil, badRequest("LitContractsClient not initialized")
+	}
+	authMethod, ok := body["authMethod"].(map[string]interface{})
+	if !ok {
+		return nil, invalidArgument("authMethod is required")
+	}
+	if _, ok := authMethod["accessToken"].(string); !ok {
+		return nil, invalidArgument("authMethod.accessToken must be a string")
+	}
+	if signer == nil {
+		return b.mint()
+	}
+
+	// The client's Signer pays for the mint transaction
+	b.mu.Lock()
+	nonce := b.minted
+	b.mu.Unlock()
+	tx := map[string]interface{}{
+		"type":                 "0x2",
+		"chainId":              hexutil.EncodeBig(yellowstoneChainID),
+		"nonce":                hexutil.EncodeUint64(uint64(nonce)),
+		"to":                   pkpNFTAddress.Hex(),
+		"value":                "0x1",
+		"data":                 hexutil.Encode(crypto.Keccak256([]byte("mintNextAndAddAuthMethods"))[:4]),
+		"gasLimit":             hexutil.EncodeUint64(500000),
+		"maxFeePerGas":         hexutil.EncodeUint64(2000000000),
+		"maxPriorityFeePerGas": hexutil.EncodeUint64(1000000000),
+	}
+	return b.requestSignature(map[string]interface{}{"transaction": tx}, func(body map[string]interface{}) (interface{}, error) {
+		raw, err := hexutil.Decode(stringField(body, "signedTransaction"))
+		if err != nil {
+			return nil, invalidArgument("invalid signed transaction")
+		}
+		var signed types.Transaction
+		if err := signed.UnmarshalBinary(raw); err != nil {
+			return nil, invalidArgument("invalid signed transaction: %v", err)
+		}
+		sender, err := types.Sender(types.LatestSignerForChainID(yellowstoneChainID), &signed)
+		if err != nil || sender != *signer {
+			return nil, invalidArgument("transaction is not signed by the signer")
+		}
+		if signed.To() == nil || *signed.To() != pkpNFTAddress || signed.Nonce() != uint64(nonce) {
+			return nil, invalidArgument("signed transaction does not match the request")
+		}
+		return b.mint()
+	})
+}
+
+// mint mints the next PKP
+func (b *Bridge) mint() (interface{}, error) {
+	b.mu.Lock()
+	b.minted++
+	n := b.minted
+	b.mu.Unlock()
+
+	// Each mint gets a new key pair, derived from its sequence number
+	seed := sha256.Sum256([]byte(fmt.Sprintf("littest pkp %d", n)))
+	pkp, err := crypto.ToECDSA(seed[:])
+	if err != nil {
+		return nil, err
+	}
+	tokenID := sha256.Sum256(crypto.FromECDSAPub(&pkp.PublicKey))
+	return map[string]interface{}{
+		"pkp": map[string]interface{}{
+			"tokenId":    "0x" + hex.EncodeToString(tokenID[:]),
+			"publicKey":  "0x" + hex.EncodeToString(crypto.FromECDSAPub(&pkp.PublicKey)),
+			"ethAddress": crypto.PubkeyToAddress(pkp.PublicKey).Hex(),
+		},
+		"tx": map[string]interface{}{
+			"hash": "0x" + digest("littest mint tx", fmt.Sprint(n)),
+		},
+	}, nil
+}
+
+func (b *Bridge) createSiweMessage(body map[string]interface{}) (interface{}, error) {
+	walletAddress := stringField(body, "walletAddress")
+	if walletAddress == "" {
+		return nil, invalidArgument("walletAddress is required")
+	}
+	message, err := siweMessage(walletAddress, stringField(body, "uri"), stringField(body, "expiration"), body["resources"])
+	if err != nil {
+		return nil, err
+	}
+	return success(map[string]interface{}{"siweMessage": message}), nil
+}
+
+// siweMessage returns the SIWE message createSiweMessage makes for
+// walletAddress and the resource ability requests resources, with a nonce
+// derived from uri
+func siweMessage(walletAddress, uri, expiration string, resources interface{}) (string, error) {
+	var requests []lit.ResourceAbilityRequest
+	if resources != nil {
+		data, _ := json.Marshal(resources)
+		if err := json.Unmarshal(data, &requests); err != nil {
+			return "", invalidArgument("invalid resources: %v", err)
+		}
+	}
+	message, err := lit.NewSiweMessage(lit.SiweMessageParams{
+		URI:           uri,
+		Expiration:    expiration,
+		Resources:     requests,
+		WalletAddress: walletAddress,
+		Nonce:         "0x" + digest("littest nonce", uri),
+		IssuedAt:      "2024-01-01T00:00:00.000Z",
+	})
+	if err != nil {
+		return "", invalidArgument("%v", err)
+	}
+	return message.String(), nil
+}
+
+// success returns a {"success": true} response with the given fields added
+func success(fields map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{"success": true}
+	for k, v := range fields {
+		result[k] = v
+	}
+	return result
+}
+
+// stringField returns a string field of a request body, or "" if it is missing
+func stringField(body map[string]interface{}, name string) string {
+	s, _ := body[name].(string)
+	return s
+}
+
+// requireSessionSigs fails the way the nodes do when a request carries no session sigs
+func requireSessionSigs(body map[string]interface{}) error {
+	if sigs, _ := body["sessionSigs"].(map[string]interface{}); len(sigs) == 0 {
+		return &Error{Message: "You must pass sessionSigs", Code: "InvalidSessionSigs", Kind: "Validation"}
+	}
+	return nil
+}
+
+// hasConditions reports whether a request carries any access control conditions
+func hasConditions(body map[string]interface{}) bool {
+	for _, name := range []string{"accessControlConditions", "evmContractConditions", "solRpcConditions", "unifiedAccessControlConditions"} {
+		if conditions, _ := body[name].([]interface{}); len(conditions) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePrivateKey parses a hex private key like ethers.Wallet does
+func parsePrivateKey(s string) (*ecdsa.PrivateKey, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, &Error{Message: "invalid private key: " + err.Error(), Code: "INVALID_ARGUMENT"}
+	}
+	return key, nil
+}
+
+// parseAddress parses an address like ethers.utils.getAddress does
+func parseAddress(s string) (common.Address, error) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, &Error{Message: fmt.Sprintf("invalid address (argument=\"address\", value=%q)", s), Code: "INVALID_ARGUMENT"}
+	}
+	return common.HexToAddress(s), nil
+}
+
+// textHash is the EIP-191 hash signed by ethers' signMessage
+func textHash(message string) []byte {
+	return crypto.Keccak256([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)))
+}
+
+// digest returns the hex SHA-256 digest of parts joined together
+func digest(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
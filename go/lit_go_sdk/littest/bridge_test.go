@@ -0,0 +1,324 @@
+package littest
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	lit "github.com/lit-protocol/lit-polyglot-sdk/go/lit_go_sdk"
+)
+
+// testPrivateKey is a well-known development key, never holding real funds
+const testPrivateKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+// testAddress is the address of testPrivateKey
+const testAddress = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+
+func newTestClient(t *testing.T) (*Bridge, *lit.LitNodeClient) {
+	t.Helper()
+	bridge := NewBridge()
+	client, err := bridge.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return bridge, client
+}
+
+func TestBridge_BasicFlow(t *testing.T) {
+	bridge, client := newTestClient(t)
+
+	if client.Server() != nil {
+		t.Fatal("Expected no Node.js server to be started")
+	}
+	if _, err := client.New(lit.LitNodeClientConfig{LitNetwork: "datil-test"}); err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := client.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	config, err := client.GetProperty("config")
+	if err != nil {
+		t.Fatalf("GetProperty() error = %v", err)
+	}
+	if network := config["property"].(map[string]interface{})["litNetwork"]; network != "datil-test" {
+		t.Errorf("litNetwork = %v, want datil-test", network)
+	}
+
+	var endpoints []string
+	for _, call := range bridge.Calls() {
+		endpoints = append(endpoints, call.Endpoint)
+	}
+	want := "/litNodeClient/new,/litNodeClient/connect,/litNodeClient/getProperty"
+	if got := strings.Join(endpoints, ","); got != want {
+		t.Errorf("Calls() = %s, want %s", got, want)
+	}
+}
+
+func TestBridge_WalletRequired(t *testing.T) {
+	_, client := newTestClient(t)
+
+	_, err := client.GetSessionSigs(lit.SessionSigsParams{Chain: "ethereum"})
+	if !errors.Is(err, lit.ErrWalletNotSet) {
+		t.Fatalf("GetSessionSigs() error = %v, want ErrWalletNotSet", err)
+	}
+}
+
+func TestBridge_SessionSigsAndExecuteJs(t *testing.T) {
+	_, client := newTestClient(t)
+
+	if _, err := client.SetAuthToken(testPrivateKey); err != nil {
+		t.Fatalf("SetAuthToken() error = %v", err)
+	}
+	params := lit.SessionSigsParams{Chain: "ethereum", Expiration: "2030-01-01T00:00:00.000Z"}
+	sessionSigs, err := client.GetSessionSigs(params)
+	if err != nil {
+		t.Fatalf("GetSessionSigs() error = %v", err)
+	}
+	secondSessionSigs, err := client.GetSessionSigs(params)
+	if err != nil {
+		t.Fatalf("GetSessionSigs() error = %v", err)
+	}
+	if len(sessionSigs) != len(nodeURLs) {
+		t.Fatalf("Expected a session sig per node, got %d", len(sessionSigs))
+	}
+	for node, sig := range sessionSigs {
+		if sig.Sig != secondSessionSigs[node].Sig {
+			t.Errorf("Expected identical requests to get identical session sigs for %s", node)
+		}
+	}
+
+	result, err := client.ExecuteJs(lit.ExecuteJsParams{Code: `Lit.Actions.setResponse({response: "ok"})`, SessionSigs: sessionSigs})
+	if err != nil {
+		t.Fatalf("ExecuteJs() error = %v", err)
+	}
+	if !result.Success {
+		t.Errorf("ExecuteJs() = %+v, want success", result)
+	}
+
+	var nodeResponses int
+	_, err = client.ExecuteJs(lit.ExecuteJsParams{
+		Code:        "1",
+		SessionSigs: sessionSigs,
+		ResponseStrategy: &lit.ResponseStrategy{
+			Strategy: lit.ResponseStrategyCustom,
+			CustomFilter: func(responses []lit.ExecuteJsResponse) (lit.ExecuteJsResponse, error) {
+				nodeResponses = len(responses)
+				return responses[0], nil
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ExecuteJs() with a custom filter error = %v", err)
+	}
+	if nodeResponses != len(nodeURLs) {
+		t.Errorf("Expected the filter to get %d node responses, got %d", len(nodeURLs), nodeResponses)
+	}
+
+	_, err = client.ExecuteJs(lit.ExecuteJsParams{Code: "1"})
+	if !errors.Is(err, lit.ErrAuthExpired) {
+		t.Errorf("ExecuteJs() without session sigs error = %v, want ErrAuthExpired", err)
+	}
+}
+
+func TestBridge_CreateSiweMessage(t *testing.T) {
+	_, client := newTestClient(t)
+
+	request := lit.LitActionResource("*").Request(lit.LitAbilityLitActionExecution)
+	result, err := client.CreateSiweMessage(lit.CreateSiweMessageParams{
+		URI:           "lit:session:abc",
+		Expiration:    "2030-01-01T00:00:00.000Z",
+		Resources:     []lit.ResourceAbilityRequest{request},
+		WalletAddress: testAddress,
+	})
+	if err != nil {
+		t.Fatalf("CreateSiweMessage() error = %v", err)
+	}
+	message, err := lit.ParseSiweMessage(result["siweMessage"].(string))
+	if err != nil {
+		t.Fatalf("ParseSiweMessage() error = %v", err)
+	}
+	recap, err := message.Recap()
+	if err != nil || recap == nil {
+		t.Fatalf("Recap() = %v, %v", recap, err)
+	}
+	if requests := recap.ResourceAbilityRequests(); len(requests) != 1 || requests[0] != request {
+		t.Errorf("ResourceAbilityRequests() = %v, want %v", requests, request)
+	}
+}
+
+func TestBridge_EncryptDecrypt(t *testing.T) {
+	_, client := newTestClient(t)
+
+	conditions := lit.Conditions{lit.WalletAddressCondition(lit.ChainEthereum, testAddress)}
+	encrypted, err := client.EncryptString(lit.EncryptStringParams{
+		DataToEncrypt:           "secret",
+		AccessControlConditions: conditions,
+	})
+	if err != nil {
+		t.Fatalf("EncryptString() error = %v", err)
+	}
+
+	decrypted, err := client.DecryptString(lit.DecryptStringParams{
+		Ciphertext:              encrypted["ciphertext"].(string),
+		DataToEncryptHash:       encrypted["dataToEncryptHash"].(string),
+		AccessControlConditions: conditions,
+		SessionSigs:             lit.SessionSigs{"node": {}},
+		Chain:                   "ethereum",
+	})
+	if err != nil {
+		t.Fatalf("DecryptString() error = %v", err)
+	}
+	if decrypted["decryptedString"] != "secret" {
+		t.Errorf("decryptedString = %v, want secret", decrypted["decryptedString"])
+	}
+}
+
+func TestBridge_MintWithAuth(t *testing.T) {
+	_, client := newTestClient(t)
+
+	signer, _ := lit.PrivateKeySignerFromHex(testPrivateKey)
+	authSig, err := lit.NewAuthSig(context.Background(), signer, "hello")
+	if err != nil {
+		t.Fatalf("NewAuthSig() error = %v", err)
+	}
+	params := lit.MintWithAuthParams{
+		AuthMethod: lit.EthWalletAuthMethod(*authSig),
+		Scopes:     []lit.AuthMethodScope{lit.AuthMethodScopeSignAnything},
+	}
+	if _, err := client.MintWithAuth(params); !errors.Is(err, lit.ErrNotInitialized) {
+		t.Fatalf("MintWithAuth() before NewLitContractsClient error = %v, want ErrNotInitialized", err)
+	}
+
+	if _, err := client.NewLitContractsClient(lit.LitContractsClientConfig{PrivateKey: testPrivateKey, Network: "datil-dev"}); err != nil {
+		t.Fatalf("NewLitContractsClient() error = %v", err)
+	}
+	// The same params can be used again
+	first, err := client.MintWithAuth(params)
+	if err != nil {
+		t.Fatalf("MintWithAuth() error = %v", err)
+	}
+	second, err := client.MintWithAuth(params)
+	if err != nil {
+		t.Fatalf("MintWithAuth() error = %v", err)
+	}
+	firstKey := first["pkp"].(map[string]interface{})["publicKey"]
+	if firstKey == second["pkp"].(map[string]interface{})["publicKey"] {
+		t.Error("Expected every mint to create a new PKP")
+	}
+}
+
+// messageSigner is a Signer that cannot sign transactions, counting the
+// messages it signs or failing with err
+type messageSigner struct {
+	key    *lit.PrivateKeySigner
+	err    error
+	signed int
+}
+
+func (s *messageSigner) Address() common.Address {
+	return s.key.Address()
+}
+
+func (s *messageSigner) SignMessage(ctx context.Context, message []byte) ([]byte, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.signed++
+	return s.key.SignMessage(ctx, message)
+}
+
+func TestBridge_Signer(t *testing.T) {
+	bridge, client := newTestClient(t)
+
+	key, err := lit.PrivateKeySignerFromHex(testPrivateKey)
+	if err != nil {
+		t.Fatalf("PrivateKeySignerFromHex() error = %v", err)
+	}
+	signer := &messageSigner{key: key}
+	if _, err := client.SetSigner(signer); err != nil {
+		t.Fatalf("SetSigner() error = %v", err)
+	}
+
+	if _, err := client.GetSessionSigs(lit.SessionSigsParams{Chain: "ethereum", Expiration: "2030-01-01T00:00:00.000Z"}); err != nil {
+		t.Fatalf("GetSessionSigs() error = %v", err)
+	}
+	authSig, err := client.GenerateAuthSig("hello")
+	if err != nil {
+		t.Fatalf("GenerateAuthSig() error = %v", err)
+	}
+	if authSig.Address != testAddress {
+		t.Errorf("authSig.address = %v, want %s", authSig.Address, testAddress)
+	}
+	if signer.signed != 2 {
+		t.Errorf("Expected the signer to sign 2 messages, got %d", signer.signed)
+	}
+
+	// Minting needs a TransactionSigner
+	if _, err := client.NewLitContractsClient(lit.LitContractsClientConfig{Network: "datil-dev"}); err != nil {
+		t.Fatalf("NewLitContractsClient() error = %v", err)
+	}
+	mintParams := lit.MintWithAuthParams{
+		AuthMethod: lit.EthWalletAuthMethod(*authSig),
+	}
+	if _, err := client.MintWithAuth(mintParams); err == nil || !strings.Contains(err.Error(), "TransactionSigner") {
+		t.Errorf("MintWithAuth() error = %v, want an error about TransactionSigner", err)
+	}
+
+	errHSM := errors.New("hsm unavailable")
+	signer.err = errHSM
+	if _, err := client.GenerateAuthSig("hello"); !errors.Is(err, errHSM) {
+		t.Errorf("GenerateAuthSig() error = %v, want the signer's error", err)
+	}
+
+	for _, call := range bridge.Calls() {
+		if strings.Contains(string(call.Body), strings.TrimPrefix(testPrivateKey, "0x")) {
+			t.Errorf("Expected the private key never to be sent, found it in %s", call.Endpoint)
+		}
+	}
+}
+
+func TestBridge_Handle(t *testing.T) {
+	bridge, client := newTestClient(t)
+
+	bridge.Handle("/litNodeClient/pkpSign", func(body map[string]interface{}) (interface{}, error) {
+		return nil, &Error{Message: "session sigs expired", Code: "InvalidSessionSigs"}
+	})
+	_, err := client.PKPSign(lit.PKPSignParams{PubKey: "0x04", ToSign: []int{1}})
+	if !errors.Is(err, lit.ErrAuthExpired) {
+		t.Fatalf("PKPSign() error = %v, want ErrAuthExpired", err)
+	}
+
+	bridge.Handle("/litNodeClient/connect", nil)
+	_, err = client.Connect()
+	var litErr *lit.LitError
+	if !errors.As(err, &litErr) || litErr.StatusCode != 404 {
+		t.Fatalf("Connect() on a removed endpoint error = %v, want a 404 LitError", err)
+	}
+}
+
+func TestBridge_ServeHTTP(t *testing.T) {
+	bridge := NewBridge()
+	srv := httptest.NewServer(bridge)
+	defer srv.Close()
+
+	port := srv.Listener.Addr().(*net.TCPAddr).Port
+	client, err := lit.NewLitNodeClient(lit.WithPort(port))
+	if err != nil {
+		t.Fatalf("NewLitNodeClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if client.Server() != nil {
+		t.Fatal("Expected the fake bridge to be reused as a running server")
+	}
+	if _, err := client.ConnectContext(context.Background()); err != nil {
+		t.Fatalf("ConnectContext() error = %v", err)
+	}
+}
@@ -0,0 +1,405 @@
+package littest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	lit "github.com/lit-protocol/lit-polyglot-sdk/go/lit_go_sdk"
+)
+
+// cassetteVersion is the version of the cassette file format
+const cassetteVersion = 1
+
+// redacted replaces the value of redacted fields in a cassette
+const redacted = "[REDACTED]"
+
+// DefaultRedactedFields are the fields holding secrets, which are never
+// written to a cassette. accessToken.sig is the signature of an auth sig
+// passed as the access token of an auth method, messageSignature the
+// signature of a message by the client's Signer.
+var DefaultRedactedFields = []string{"authToken", "privateKey", "authSig.sig", "accessToken.sig", "messageSignature"}
+
+// DefaultIgnoredFields are the fields left out when matching requests against
+// a cassette, because they change from one run to the next
+var DefaultIgnoredFields = []string{"expiration", "expirationTime", "issuedAt", "nonce"}
+
+// lifecycleEndpoints are the endpoints used to manage the server rather than
+// to talk to Lit, which are neither recorded nor replayed
+var lifecycleEndpoints = map[string]bool{
+	"/isReady":   true,
+	"/handshake": true,
+	"/shutdown":  true,
+}
+
+// Interaction is a request to the bridge and its response, as stored in a cassette
+type Interaction struct {
+	// Endpoint is the endpoint called, e.g. /litNodeClient/executeJs
+	Endpoint string `json:"endpoint"`
+	// Request is the redacted JSON payload of the request, if any
+	Request json.RawMessage `json:"request,omitempty"`
+	// StatusCode is the HTTP status code of the response
+	StatusCode int `json:"statusCode"`
+	// Response is the redacted JSON body of the response, if it is JSON
+	Response json.RawMessage `json:"response,omitempty"`
+	// ResponseText is the body of the response, if it is not JSON
+	ResponseText string `json:"responseText,omitempty"`
+}
+
+// cassetteFile is the on-disk format of a cassette
+type cassetteFile struct {
+	Version      int           `json:"version"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Matcher reports whether a recorded interaction answers a request. The
+// request body has already been normalized like the recorded one.
+type Matcher func(recorded Interaction, endpoint string, body json.RawMessage) bool
+
+// CassetteOption configures a Recorder or a Cassette
+type CassetteOption func(*cassetteConfig)
+
+// cassetteConfig holds the settings collected from CassetteOptions
+type cassetteConfig struct {
+	redact  []string
+	ignore  []string
+	matcher Matcher
+}
+
+// newCassetteConfig applies opts to the default settings
+func newCassetteConfig(opts []CassetteOption) cassetteConfig {
+	config := cassetteConfig{
+		redact: append([]string(nil), DefaultRedactedFields...),
+		ignore: append([]string(nil), DefaultIgnoredFields...),
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// RedactFields adds fields to redact from requests and responses. A field is
+// a key, matched at any depth, optionally preceded by the keys of its parents,
+// e.g. "authSig.sig".
+func RedactFields(fields ...string) CassetteOption {
+	return func(c *cassetteConfig) {
+		c.redact = append(c.redact, fields...)
+	}
+}
+
+// IgnoreFields adds fields to leave out when matching requests, in the same
+// form as RedactFields. By default DefaultIgnoredFields are ignored.
+func IgnoreFields(fields ...string) CassetteOption {
+	return func(c *cassetteConfig) {
+		c.ignore = append(c.ignore, fields...)
+	}
+}
+
+// WithMatcher replaces the default matching on the endpoint and normalized body
+func WithMatcher(m Matcher) CassetteOption {
+	return func(c *cassetteConfig) {
+		c.matcher = m
+	}
+}
+
+// Recorder records the traffic of a LitNodeClient so it can be replayed with
+// a Cassette. Secrets are redacted as it records.
+//
+//	rec := littest.NewRecorder("testdata/flow.json")
+//	client, err := lit_go_sdk.NewLitNodeClient(lit_go_sdk.WithTransportWrapper(rec.Wrap))
+//	// ... use the client against the real network
+//	err = rec.Save()
+type Recorder struct {
+	path   string
+	config cassetteConfig
+
+	mu           sync.Mutex
+	interactions []Interaction
+}
+
+// NewRecorder returns a Recorder that saves its cassette to path
+func NewRecorder(path string, opts ...CassetteOption) *Recorder {
+	return &Recorder{path: path, config: newCassetteConfig(opts)}
+}
+
+// Wrap returns a transport recording the traffic sent through next. Pass it
+// to lit_go_sdk.WithTransportWrapper.
+func (r *Recorder) Wrap(next lit.Transport) lit.Transport {
+	return recordingTransport{recorder: r, next: next}
+}
+
+// Interactions returns the interactions recorded so far
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded interactions to the cassette file, creating its
+// directory if needed
+func (r *Recorder) Save() error {
+	data, err := json.MarshalIndent(cassetteFile{Version: cassetteVersion, Interactions: r.Interactions()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cassette directory: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write cassette: %w", err)
+	}
+	return nil
+}
+
+// record adds an interaction, redacting its secrets
+func (r *Recorder) record(endpoint string, body []byte, statusCode int, respBody []byte) {
+	interaction := Interaction{Endpoint: endpoint, StatusCode: statusCode}
+	if len(bytes.TrimSpace(body)) > 0 {
+		interaction.Request = redactJSON(body, r.config.redact)
+	}
+	if json.Valid(respBody) {
+		interaction.Response = redactJSON(respBody, r.config.redact)
+	} else {
+		interaction.ResponseText = string(respBody)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, interaction)
+}
+
+// recordingTransport is the Transport returned by Recorder.Wrap
+type recordingTransport struct {
+	recorder *Recorder
+	next     lit.Transport
+}
+
+// Post implements lit_go_sdk.Transport
+func (t recordingTransport) Post(ctx context.Context, endpoint string, body []byte) (int, []byte, error) {
+	statusCode, respBody, err := t.next.Post(ctx, endpoint, body)
+	if err != nil || lifecycleEndpoints[endpoint] {
+		return statusCode, respBody, err
+	}
+	t.recorder.record(endpoint, body, statusCode, respBody)
+	return statusCode, respBody, nil
+}
+
+// Cassette replays recorded interactions. It implements lit_go_sdk.Transport,
+// to be used with lit_go_sdk.WithTransport, so no Node.js server or network
+// access is needed.
+//
+// Each request is answered by the first interaction not used yet that has the
+// same endpoint and, once redacted fields are masked and ignored fields left
+// out, the same JSON body. When all matching interactions have been used, the
+// last one is replayed again.
+type Cassette struct {
+	config cassetteConfig
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// LoadCassette reads a cassette saved by a Recorder
+func LoadCassette(path string, opts ...CassetteOption) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to decode cassette %s: %w", path, err)
+	}
+	if file.Version != cassetteVersion {
+		return nil, fmt.Errorf("cassette %s has version %d, want %d", path, file.Version, cassetteVersion)
+	}
+	return NewCassette(file.Interactions, opts...), nil
+}
+
+// NewCassette returns a Cassette replaying interactions
+func NewCassette(interactions []Interaction, opts ...CassetteOption) *Cassette {
+	return &Cassette{
+		config:       newCassetteConfig(opts),
+		interactions: interactions,
+		used:         make([]bool, len(interactions)),
+	}
+}
+
+// NewClient returns a LitNodeClient replaying the cassette
+func (c *Cassette) NewClient(opts ...lit.Option) (*lit.LitNodeClient, error) {
+	return lit.NewLitNodeClient(append(opts, lit.WithTransport(c))...)
+}
+
+// Post implements lit_go_sdk.Transport
+func (c *Cassette) Post(ctx context.Context, endpoint string, body []byte) (int, []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, nil, err
+	}
+	var normalized json.RawMessage
+	if len(bytes.TrimSpace(body)) > 0 {
+		normalized = c.normalize(redactJSON(body, c.config.redact))
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	last := -1
+	for i, interaction := range c.interactions {
+		if !c.matches(interaction, endpoint, normalized) {
+			continue
+		}
+		last = i
+		if !c.used[i] {
+			c.used[i] = true
+			return replay(interaction)
+		}
+	}
+	if last >= 0 {
+		return replay(c.interactions[last])
+	}
+	return 0, nil, fmt.Errorf("littest: no recorded interaction matches %s %s", endpoint, normalized)
+}
+
+// matches reports whether interaction answers the normalized request
+func (c *Cassette) matches(interaction Interaction, endpoint string, body json.RawMessage) bool {
+	if c.config.matcher != nil {
+		return c.config.matcher(interaction, endpoint, body)
+	}
+	if interaction.Endpoint != endpoint {
+		return false
+	}
+	var recorded json.RawMessage
+	if len(interaction.Request) > 0 {
+		recorded = c.normalize(interaction.Request)
+	}
+	return bytes.Equal(recorded, body)
+}
+
+// normalize leaves out ignored fields and re-encodes body canonically, with
+// sorted keys and without insignificant whitespace
+func (c *Cassette) normalize(body json.RawMessage) json.RawMessage {
+	var value interface{}
+	if err := decodeJSON(body, &value); err != nil {
+		return body
+	}
+	value, _ = rewriteFields(value, nil, c.config.ignore, nil)
+	normalized, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return normalized
+}
+
+// replay returns the response of a recorded interaction
+func replay(interaction Interaction) (int, []byte, error) {
+	if interaction.Response != nil {
+		return interaction.StatusCode, interaction.Response, nil
+	}
+	return interaction.StatusCode, []byte(interaction.ResponseText), nil
+}
+
+// redactJSON replaces the values of the given fields in a JSON document. JSON
+// objects encoded in strings, like the access token of an auth method, are
+// redacted too. body is returned unchanged if it is not JSON.
+func redactJSON(body []byte, fields []string) json.RawMessage {
+	var value interface{}
+	if err := decodeJSON(body, &value); err != nil {
+		return body
+	}
+	value, _ = rewriteFields(value, nil, fields, redacted)
+	redactedBody, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return redactedBody
+}
+
+// rewriteFields walks value, with path the keys leading to it, and replaces
+// the fields matching one of fields with replacement, or removes them if
+// replacement is nil. It also reports whether anything was rewritten.
+func rewriteFields(value interface{}, path []string, fields []string, replacement interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		changed := false
+		for key, child := range v {
+			childPath := append(append([]string(nil), path...), key)
+			if fieldMatches(childPath, fields) {
+				if replacement != nil {
+					out[key] = replacement
+				}
+				changed = true
+				continue
+			}
+			var childChanged bool
+			out[key], childChanged = rewriteFields(child, childPath, fields, replacement)
+			changed = changed || childChanged
+		}
+		return out, changed
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		changed := false
+		for i, child := range v {
+			var childChanged bool
+			out[i], childChanged = rewriteFields(child, path, fields, replacement)
+			changed = changed || childChanged
+		}
+		return out, changed
+	case string:
+		// A stringified JSON object, e.g. an auth sig passed as an access token.
+		// It is left untouched unless one of its fields is rewritten, so signed
+		// strings like session sig messages keep their exact bytes.
+		if !strings.HasPrefix(strings.TrimSpace(v), "{") {
+			return v, false
+		}
+		var inner interface{}
+		if err := decodeJSON([]byte(v), &inner); err != nil {
+			return v, false
+		}
+		rewritten, changed := rewriteFields(inner, path, fields, replacement)
+		if !changed {
+			return v, false
+		}
+		encoded, err := json.Marshal(rewritten)
+		if err != nil {
+			return v, false
+		}
+		return string(encoded), true
+	default:
+		return v, false
+	}
+}
+
+// decodeJSON decodes data into v keeping numbers as json.Number, so large
+// integers such as token IDs survive being encoded again
+func decodeJSON(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// fieldMatches reports whether the keys in path end with one of fields
+func fieldMatches(path []string, fields []string) bool {
+	for _, field := range fields {
+		keys := strings.Split(field, ".")
+		if len(keys) > len(path) {
+			continue
+		}
+		matched := true
+		for i, key := range keys {
+			if path[len(path)-len(keys)+i] != key {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
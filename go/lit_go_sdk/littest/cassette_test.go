@@ -0,0 +1,113 @@
+package littest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	lit "github.com/lit-protocol/lit-polyglot-sdk/go/lit_go_sdk"
+)
+
+// recordFlow runs a short flow against a fake bridge while recording it
+func recordFlow(t *testing.T, path string) (sessionSigs lit.SessionSigs, authSig *lit.AuthSig) {
+	t.Helper()
+
+	rec := NewRecorder(path)
+	client, err := lit.NewLitNodeClient(lit.WithTransport(NewBridge()), lit.WithTransportWrapper(rec.Wrap))
+	if err != nil {
+		t.Fatalf("NewLitNodeClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.SetAuthToken(testPrivateKey); err != nil {
+		t.Fatalf("SetAuthToken() error = %v", err)
+	}
+	sessionSigs, err = client.GetSessionSigs(lit.SessionSigsParams{Chain: "ethereum", Expiration: "2030-01-01T00:00:00.000Z"})
+	if err != nil {
+		t.Fatalf("GetSessionSigs() error = %v", err)
+	}
+	// The auth sig is made locally, minting with it sends it to the bridge
+	authSig, err = client.GenerateAuthSig("hello")
+	if err != nil {
+		t.Fatalf("GenerateAuthSig() error = %v", err)
+	}
+	if _, err := client.MintWithAuth(lit.MintWithAuthParams{AuthMethod: lit.EthWalletAuthMethod(*authSig)}); err != nil {
+		t.Fatalf("MintWithAuth() error = %v", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	return sessionSigs, authSig
+}
+
+func TestCassette_RecordRedactsSecrets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "flow.json")
+	_, authSig := recordFlow(t, path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read cassette: %v", err)
+	}
+	cassette := string(data)
+	for _, secret := range []string{strings.TrimPrefix(testPrivateKey, "0x"), authSig.Sig} {
+		if strings.Contains(cassette, secret) {
+			t.Errorf("Expected %s to be redacted from the cassette", secret)
+		}
+	}
+	if !strings.Contains(cassette, redacted) {
+		t.Error("Expected redacted fields in the cassette")
+	}
+}
+
+func TestCassette_Replay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flow.json")
+	sessionSigs, _ := recordFlow(t, path)
+
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("LoadCassette() error = %v", err)
+	}
+	client, err := cassette.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	defer client.Close()
+
+	// Signatures are redacted and the expiration ignored when matching
+	if _, err := client.SetAuthToken(testPrivateKey); err != nil {
+		t.Fatalf("SetAuthToken() error = %v", err)
+	}
+	replayed, err := client.GetSessionSigs(lit.SessionSigsParams{Chain: "ethereum", Expiration: "2031-06-01T00:00:00.000Z"})
+	if err != nil {
+		t.Fatalf("GetSessionSigs() error = %v", err)
+	}
+	if !reflect.DeepEqual(replayed, sessionSigs) {
+		t.Error("Expected the recorded session sigs to be replayed unchanged")
+	}
+
+	if _, err := client.GetSessionSigs(lit.SessionSigsParams{Chain: "solana"}); err == nil {
+		t.Error("Expected an error for a request that was not recorded")
+	}
+}
+
+func TestCassette_RedactsStringifiedAuthSig(t *testing.T) {
+	accessToken := `{"sig":"0xsecret","derivedVia":"web3.eth.personal.sign","signedMessage":"hi","address":"0x1"}`
+	got := string(redactJSON([]byte(`{"authMethod":{"authMethodType":1,"accessToken":`+quote(accessToken)+`}}`), DefaultRedactedFields))
+	if strings.Contains(got, "0xsecret") {
+		t.Errorf("Expected the auth sig inside the access token to be redacted, got %s", got)
+	}
+
+	// Strings without redacted fields keep their exact bytes
+	signedMessage := `{"z":1,"a":2}`
+	got = string(redactJSON([]byte(`{"signedMessage":`+quote(signedMessage)+`}`), DefaultRedactedFields))
+	if !strings.Contains(got, quote(signedMessage)) {
+		t.Errorf("Expected the signed message to be left untouched, got %s", got)
+	}
+}
+
+// quote encodes s as a JSON string
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
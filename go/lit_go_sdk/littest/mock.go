@@ -0,0 +1,353 @@
+package littest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	lit "github.com/lit-protocol/lit-polyglot-sdk/go/lit_go_sdk"
+)
+
+// MockCall is a method call recorded by a Mock
+type MockCall struct {
+	// Method is the name of the method without the Context suffix, e.g. ExecuteJs
+	Method string
+	// Arg is the argument passed to the method, e.g. an lit.ExecuteJsParams,
+	// or nil for methods without one
+	Arg interface{}
+}
+
+// MockFunc computes the response of a Mock method from its argument. The
+// result is a map[string]interface{} like the server responses, or the typed
+// result of the method, e.g. an *lit.ExecuteJsResult for ExecuteJs. Results of
+// another type are converted through JSON.
+type MockFunc func(ctx context.Context, arg interface{}) (interface{}, error)
+
+// Mock is an in-memory implementation of lit.LitClient. It records every call
+// and answers with scripted responses, by default {"success": true}. Methods
+// are named without their Context suffix: scripting ExecuteJs also scripts
+// ExecuteJsContext. It is safe for concurrent use.
+//
+//	mock := littest.NewMock()
+//	mock.Return("ExecuteJs", nil, &lit.LitError{Code: "InvalidSessionSigs", Message: "expired"})
+type Mock struct {
+	mu       sync.Mutex
+	calls    []MockCall
+	queued   map[string][]MockFunc
+	handlers map[string]MockFunc
+	closed   bool
+}
+
+var _ lit.LitClient = (*Mock)(nil)
+
+// NewMock returns a Mock answering every call with {"success": true}
+func NewMock() *Mock {
+	return &Mock{
+		queued:   make(map[string][]MockFunc),
+		handlers: make(map[string]MockFunc),
+	}
+}
+
+// Return makes every following call to method return result and err. See
+// MockFunc for the types result can have.
+func (m *Mock) Return(method string, result interface{}, err error) {
+	m.Handle(method, func(context.Context, interface{}) (interface{}, error) {
+		return result, err
+	})
+}
+
+// ReturnOnce makes the next call to method return result and err. Responses
+// queued with ReturnOnce are used in order before the one set with Return or Handle.
+func (m *Mock) ReturnOnce(method string, result interface{}, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queued[method] = append(m.queued[method], func(context.Context, interface{}) (interface{}, error) {
+		return result, err
+	})
+}
+
+// Handle makes every following call to method answer with fn
+func (m *Mock) Handle(method string, fn MockFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[method] = fn
+}
+
+// Calls returns the calls made so far, in order
+func (m *Mock) Calls() []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockCall(nil), m.calls...)
+}
+
+// CallsTo returns the calls made so far to method, in order
+func (m *Mock) CallsTo(method string) []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var calls []MockCall
+	for _, call := range m.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Closed reports whether Close has been called
+func (m *Mock) Closed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+// call records a call and returns its scripted response
+func (m *Mock) call(ctx context.Context, method string, arg interface{}) (interface{}, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, MockCall{Method: method, Arg: arg})
+	fn := m.handlers[method]
+	if queued := m.queued[method]; len(queued) > 0 {
+		fn = queued[0]
+		m.queued[method] = queued[1:]
+	}
+	m.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if fn == nil {
+		return map[string]interface{}{"success": true}, nil
+	}
+	return fn(ctx, arg)
+}
+
+// callMap is call for the methods returning a generic server response
+func (m *Mock) callMap(ctx context.Context, method string, arg interface{}) (map[string]interface{}, error) {
+	result, err := m.call(ctx, method, arg)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	if resultMap, ok := result.(map[string]interface{}); ok {
+		return resultMap, nil
+	}
+	var resultMap map[string]interface{}
+	if err := convert(result, &resultMap); err != nil {
+		return nil, fmt.Errorf("littest: scripted %s result: %w", method, err)
+	}
+	return resultMap, nil
+}
+
+// convert converts a scripted result to the type of out through JSON
+func convert(result interface{}, out interface{}) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// ExecuteJs implements lit.Executor
+func (m *Mock) ExecuteJs(params lit.ExecuteJsParams) (*lit.ExecuteJsResult, error) {
+	return m.ExecuteJsContext(context.Background(), params)
+}
+
+// ExecuteJsContext implements lit.Executor
+func (m *Mock) ExecuteJsContext(ctx context.Context, params lit.ExecuteJsParams) (*lit.ExecuteJsResult, error) {
+	result, err := m.call(ctx, "ExecuteJs", params)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	if executeJsResult, ok := result.(*lit.ExecuteJsResult); ok {
+		return executeJsResult, nil
+	}
+	var executeJsResult lit.ExecuteJsResult
+	if err := convert(result, &executeJsResult); err != nil {
+		return nil, fmt.Errorf("littest: scripted ExecuteJs result: %w", err)
+	}
+	return &executeJsResult, nil
+}
+
+// PKPSign implements lit.PKPSigner
+func (m *Mock) PKPSign(params lit.PKPSignParams) (map[string]interface{}, error) {
+	return m.PKPSignContext(context.Background(), params)
+}
+
+// PKPSignContext implements lit.PKPSigner
+func (m *Mock) PKPSignContext(ctx context.Context, params lit.PKPSignParams) (map[string]interface{}, error) {
+	return m.callMap(ctx, "PKPSign", params)
+}
+
+// EncryptString implements lit.Encryptor
+func (m *Mock) EncryptString(params lit.EncryptStringParams) (map[string]interface{}, error) {
+	return m.EncryptStringContext(context.Background(), params)
+}
+
+// EncryptStringContext implements lit.Encryptor
+func (m *Mock) EncryptStringContext(ctx context.Context, params lit.EncryptStringParams) (map[string]interface{}, error) {
+	return m.callMap(ctx, "EncryptString", params)
+}
+
+// DecryptString implements lit.Encryptor
+func (m *Mock) DecryptString(params lit.DecryptStringParams) (map[string]interface{}, error) {
+	return m.DecryptStringContext(context.Background(), params)
+}
+
+// DecryptStringContext implements lit.Encryptor
+func (m *Mock) DecryptStringContext(ctx context.Context, params lit.DecryptStringParams) (map[string]interface{}, error) {
+	return m.callMap(ctx, "DecryptString", params)
+}
+
+// NewLitContractsClient implements lit.Minter
+func (m *Mock) NewLitContractsClient(config lit.LitContractsClientConfig) (map[string]interface{}, error) {
+	return m.NewLitContractsClientContext(context.Background(), config)
+}
+
+// NewLitContractsClientContext implements lit.Minter
+func (m *Mock) NewLitContractsClientContext(ctx context.Context, config lit.LitContractsClientConfig) (map[string]interface{}, error) {
+	return m.callMap(ctx, "NewLitContractsClient", config)
+}
+
+// MintWithAuth implements lit.Minter
+func (m *Mock) MintWithAuth(params lit.MintWithAuthParams) (map[string]interface{}, error) {
+	return m.MintWithAuthContext(context.Background(), params)
+}
+
+// MintWithAuthContext implements lit.Minter
+func (m *Mock) MintWithAuthContext(ctx context.Context, params lit.MintWithAuthParams) (map[string]interface{}, error) {
+	return m.callMap(ctx, "MintWithAuth", params)
+}
+
+// SetAuthToken implements lit.Authenticator
+func (m *Mock) SetAuthToken(authToken string) (map[string]interface{}, error) {
+	return m.SetAuthTokenContext(context.Background(), authToken)
+}
+
+// SetAuthTokenContext implements lit.Authenticator
+func (m *Mock) SetAuthTokenContext(ctx context.Context, authToken string) (map[string]interface{}, error) {
+	return m.callMap(ctx, "SetAuthToken", authToken)
+}
+
+// SetSigner implements lit.Authenticator
+func (m *Mock) SetSigner(signer lit.Signer) (map[string]interface{}, error) {
+	return m.SetSignerContext(context.Background(), signer)
+}
+
+// SetSignerContext implements lit.Authenticator
+func (m *Mock) SetSignerContext(ctx context.Context, signer lit.Signer) (map[string]interface{}, error) {
+	return m.callMap(ctx, "SetSigner", signer)
+}
+
+// GetSessionSigs implements lit.Authenticator
+func (m *Mock) GetSessionSigs(params lit.SessionSigsParams) (lit.SessionSigs, error) {
+	return m.GetSessionSigsContext(context.Background(), params)
+}
+
+// GetSessionSigsContext implements lit.Authenticator. A scripted result of
+// the shape the server returns, with the sigs under "sessionSigs", is
+// accepted too.
+func (m *Mock) GetSessionSigsContext(ctx context.Context, params lit.SessionSigsParams) (lit.SessionSigs, error) {
+	result, err := m.call(ctx, "GetSessionSigs", params)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	switch result := result.(type) {
+	case lit.SessionSigs:
+		return result, nil
+	case map[string]interface{}:
+		if _, ok := result["sessionSigs"]; ok {
+			return lit.SessionSigsFromResult(result)
+		}
+	}
+	var sessionSigs lit.SessionSigs
+	if err := convert(result, &sessionSigs); err != nil {
+		return nil, fmt.Errorf("littest: scripted GetSessionSigs result: %w", err)
+	}
+	return sessionSigs, nil
+}
+
+// CreateSiweMessage implements lit.Authenticator
+func (m *Mock) CreateSiweMessage(params lit.CreateSiweMessageParams) (map[string]interface{}, error) {
+	return m.CreateSiweMessageContext(context.Background(), params)
+}
+
+// CreateSiweMessageContext implements lit.Authenticator
+func (m *Mock) CreateSiweMessageContext(ctx context.Context, params lit.CreateSiweMessageParams) (map[string]interface{}, error) {
+	return m.callMap(ctx, "CreateSiweMessage", params)
+}
+
+// GenerateAuthSig implements lit.Authenticator
+func (m *Mock) GenerateAuthSig(toSign string) (*lit.AuthSig, error) {
+	return m.GenerateAuthSigContext(context.Background(), toSign)
+}
+
+// GenerateAuthSigContext implements lit.Authenticator. The scripted result
+// can be a *lit.AuthSig, a lit.AuthSig, or a map holding it under authSig.
+func (m *Mock) GenerateAuthSigContext(ctx context.Context, toSign string) (*lit.AuthSig, error) {
+	result, err := m.call(ctx, "GenerateAuthSig", toSign)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	switch result := result.(type) {
+	case *lit.AuthSig:
+		return result, nil
+	case map[string]interface{}:
+		if _, ok := result["authSig"]; ok {
+			return lit.AuthSigFromResult(result)
+		}
+	}
+	var authSig lit.AuthSig
+	if err := convert(result, &authSig); err != nil {
+		return nil, fmt.Errorf("littest: scripted GenerateAuthSig result: %w", err)
+	}
+	return &authSig, nil
+}
+
+// New implements lit.LitClient
+func (m *Mock) New(config lit.LitNodeClientConfig) (map[string]interface{}, error) {
+	return m.NewContext(context.Background(), config)
+}
+
+// NewContext implements lit.LitClient
+func (m *Mock) NewContext(ctx context.Context, config lit.LitNodeClientConfig) (map[string]interface{}, error) {
+	return m.callMap(ctx, "New", config)
+}
+
+// Connect implements lit.LitClient
+func (m *Mock) Connect() (map[string]interface{}, error) {
+	return m.ConnectContext(context.Background())
+}
+
+// ConnectContext implements lit.LitClient
+func (m *Mock) ConnectContext(ctx context.Context) (map[string]interface{}, error) {
+	return m.callMap(ctx, "Connect", nil)
+}
+
+// Disconnect implements lit.LitClient
+func (m *Mock) Disconnect() (map[string]interface{}, error) {
+	return m.DisconnectContext(context.Background())
+}
+
+// DisconnectContext implements lit.LitClient
+func (m *Mock) DisconnectContext(ctx context.Context) (map[string]interface{}, error) {
+	return m.callMap(ctx, "Disconnect", nil)
+}
+
+// GetProperty implements lit.LitClient
+func (m *Mock) GetProperty(property string) (map[string]interface{}, error) {
+	return m.GetPropertyContext(context.Background(), property)
+}
+
+// GetPropertyContext implements lit.LitClient
+func (m *Mock) GetPropertyContext(ctx context.Context, property string) (map[string]interface{}, error) {
+	return m.callMap(ctx, "GetProperty", property)
+}
+
+// Close implements lit.LitClient. It is recorded like the other methods but
+// cannot be scripted.
+func (m *Mock) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, MockCall{Method: "Close"})
+	m.closed = true
+	return nil
+}
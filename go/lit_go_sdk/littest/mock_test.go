@@ -0,0 +1,87 @@
+package littest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	lit "github.com/lit-protocol/lit-polyglot-sdk/go/lit_go_sdk"
+)
+
+// runAction stands in for consumer code depending on an interface of the SDK
+func runAction(executor lit.Executor, code string) (interface{}, error) {
+	result, err := executor.ExecuteJs(lit.ExecuteJsParams{Code: code})
+	if err != nil {
+		return nil, err
+	}
+	return result.Response.String(), nil
+}
+
+func TestMock_RecordsCalls(t *testing.T) {
+	mock := NewMock()
+
+	if _, err := mock.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if _, err := runAction(mock, "code"); err != nil {
+		t.Fatalf("runAction() error = %v", err)
+	}
+	if _, err := mock.ExecuteJsContext(context.Background(), lit.ExecuteJsParams{Code: "other"}); err != nil {
+		t.Fatalf("ExecuteJsContext() error = %v", err)
+	}
+	mock.Close()
+
+	if got := len(mock.Calls()); got != 4 {
+		t.Errorf("len(Calls()) = %d, want 4", got)
+	}
+	calls := mock.CallsTo("ExecuteJs")
+	if len(calls) != 2 {
+		t.Fatalf("len(CallsTo(ExecuteJs)) = %d, want 2", len(calls))
+	}
+	if params := calls[1].Arg.(lit.ExecuteJsParams); params.Code != "other" {
+		t.Errorf("second ExecuteJs code = %q, want other", params.Code)
+	}
+	if !mock.Closed() {
+		t.Error("Expected Closed() to report the Close call")
+	}
+}
+
+func TestMock_ScriptedResponses(t *testing.T) {
+	mock := NewMock()
+	expired := &lit.LitError{Endpoint: "/litNodeClient/executeJs", Code: "InvalidSessionSigs", Message: "session sigs expired"}
+
+	mock.Return("ExecuteJs", map[string]interface{}{"response": "ok"}, nil)
+	mock.ReturnOnce("ExecuteJs", nil, expired)
+
+	if _, err := runAction(mock, "code"); !errors.Is(err, lit.ErrAuthExpired) {
+		t.Fatalf("first runAction() error = %v, want ErrAuthExpired", err)
+	}
+	response, err := runAction(mock, "code")
+	if err != nil {
+		t.Fatalf("second runAction() error = %v", err)
+	}
+	if response != "ok" {
+		t.Errorf("response = %v, want ok", response)
+	}
+
+	mock.Handle("GetProperty", func(_ context.Context, arg interface{}) (interface{}, error) {
+		return map[string]interface{}{"property": arg}, nil
+	})
+	result, err := mock.GetProperty("ready")
+	if err != nil {
+		t.Fatalf("GetProperty() error = %v", err)
+	}
+	if result["property"] != "ready" {
+		t.Errorf("property = %v, want ready", result["property"])
+	}
+}
+
+func TestMock_ContextCancelled(t *testing.T) {
+	mock := NewMock()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := mock.PKPSignContext(ctx, lit.PKPSignParams{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("PKPSignContext() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,249 @@
+package lit_go_sdk
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	// defaultPort is the port the Node.js server listens on unless told otherwise
+	defaultPort = 3092
+	// defaultStartupTimeout is how long NewLitNodeClient waits for the server to become ready
+	defaultStartupTimeout = 10 * time.Second
+	// defaultNodePath is the Node.js executable, looked up in PATH
+	defaultNodePath = "node"
+	// defaultShutdownGracePeriod is how long the server gets to exit after SIGTERM
+	defaultShutdownGracePeriod = 5 * time.Second
+)
+
+// Option configures a LitNodeClient created with NewLitNodeClient
+type Option func(*clientOptions)
+
+// clientOptions holds the settings collected from the Options passed to NewLitNodeClient
+type clientOptions struct {
+	port           int
+	nodePath       string
+	serverPath     string
+	cacheDir       string
+	startupTimeout time.Duration
+	env            []string
+	dir            string
+	httpClient     *http.Client
+	transport      Transport
+	wrapTransport  func(Transport) Transport
+	secret         string
+	unixSocket     bool
+	onServerExit   func(*ExitError)
+	gracePeriod    time.Duration
+
+	restartMismatchedBridge bool
+
+	autoSessionSigs bool
+	sessionOptions  []SessionOption
+
+	autoRestart       bool
+	restartMinBackoff time.Duration
+	restartMaxBackoff time.Duration
+}
+
+// defaultClientOptions returns the settings used when no Options are given
+func defaultClientOptions() clientOptions {
+	return clientOptions{
+		port:           defaultPort,
+		nodePath:       defaultNodePath,
+		startupTimeout: defaultStartupTimeout,
+		httpClient:     http.DefaultClient,
+		gracePeriod:    defaultShutdownGracePeriod,
+		secret:         os.Getenv(bridgeSecretEnv),
+
+		autoRestart:       true,
+		restartMinBackoff: defaultRestartMinBackoff,
+		restartMaxBackoff: defaultRestartMaxBackoff,
+	}
+}
+
+// WithPort sets the port the Node.js server listens on. If a server is already
+// running on that port it is reused. Defaults to 3092.
+func WithPort(port int) Option {
+	return func(o *clientOptions) {
+		o.port = port
+	}
+}
+
+// WithEphemeralPort makes the client start its own Node.js server on a free
+// port picked by the operating system, so several clients can run on one host
+func WithEphemeralPort() Option {
+	return func(o *clientOptions) {
+		o.port = 0
+	}
+}
+
+// WithUnixSocket makes the client start its own Node.js server listening on a
+// Unix domain socket instead of a TCP port. The socket lives in a private
+// temporary directory only accessible to the current user and is removed by
+// Close. Ports set with WithPort or WithEphemeralPort are ignored.
+func WithUnixSocket() Option {
+	return func(o *clientOptions) {
+		o.unixSocket = true
+	}
+}
+
+// WithRestartMismatchedBridge controls what happens when the server already
+// running on the port reports a version other than BridgeVersion, or runs as
+// another user. By default NewLitNodeClient fails with a *BridgeMismatchError.
+// When enabled, the client asks that server to shut down and starts its own.
+// The old server must accept the client's bridge secret for this to work.
+func WithRestartMismatchedBridge(enabled bool) Option {
+	return func(o *clientOptions) {
+		o.restartMismatchedBridge = enabled
+	}
+}
+
+// WithAutoSessionSigs gives the client a SessionManager, configured with
+// opts, that ExecuteJs, PKPSign and DecryptString get session sigs from when
+// none are passed. The sigs grant the ability each operation needs over every
+// resource of its kind, and are cached until shortly before they expire. An
+// auth token must be set with SetAuthToken before they can be obtained.
+func WithAutoSessionSigs(opts ...SessionOption) Option {
+	return func(o *clientOptions) {
+		o.autoSessionSigs = true
+		o.sessionOptions = append(o.sessionOptions, opts...)
+	}
+}
+
+// WithNodePath sets the Node.js executable used to run the server. Defaults to
+// "node" looked up in PATH.
+func WithNodePath(path string) Option {
+	return func(o *clientOptions) {
+		o.nodePath = path
+	}
+}
+
+// WithServerPath sets the path of the JS SDK server script to run instead of
+// the bundle embedded in the Go SDK
+func WithServerPath(path string) Option {
+	return func(o *clientOptions) {
+		o.serverPath = path
+	}
+}
+
+// WithCacheDir sets the directory the embedded JS SDK server bundle is
+// extracted to. Defaults to a lit-go-sdk directory in the user cache
+// directory, or in the system temp directory if that is not writable.
+func WithCacheDir(dir string) Option {
+	return func(o *clientOptions) {
+		o.cacheDir = dir
+	}
+}
+
+// WithStartupTimeout sets how long to wait for the Node.js server to become
+// ready. Defaults to 10 seconds.
+func WithStartupTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.startupTimeout = timeout
+	}
+}
+
+// WithEnv adds environment variables, in "KEY=value" form, to the environment
+// the Node.js server is started with. The server always inherits the
+// environment of the current process.
+func WithEnv(env ...string) Option {
+	return func(o *clientOptions) {
+		o.env = append(o.env, env...)
+	}
+}
+
+// WithWorkingDir sets the working directory of the Node.js server process.
+// Defaults to the directory containing the server script.
+func WithWorkingDir(dir string) Option {
+	return func(o *clientOptions) {
+		o.dir = dir
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to talk to the Node.js server.
+// Defaults to http.DefaultClient.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *clientOptions) {
+		o.httpClient = client
+	}
+}
+
+// WithTransport makes the client send every request through t instead of
+// HTTP. The client then neither starts nor looks for a Node.js server, and
+// options about the server, port and HTTP client are ignored.
+func WithTransport(t Transport) Option {
+	return func(o *clientOptions) {
+		o.transport = t
+	}
+}
+
+// WithTransportWrapper wraps the transport the client sends requests through,
+// the default HTTP transport or the one set with WithTransport, e.g. to record
+// the traffic with a littest.Recorder. Unlike WithTransport it keeps the
+// client managing its Node.js server as usual.
+func WithTransportWrapper(wrap func(Transport) Transport) Option {
+	return func(o *clientOptions) {
+		o.wrapTransport = wrap
+	}
+}
+
+// WithBridgeSecret sets the secret shared with the Node.js server. Every
+// request carries it and the server rejects requests without it. By default
+// the LIT_BRIDGE_SECRET environment variable is used if set, otherwise a
+// random secret is generated for each server the client starts. Set it to
+// reuse a server started elsewhere with the same secret.
+func WithBridgeSecret(secret string) Option {
+	return func(o *clientOptions) {
+		o.secret = secret
+	}
+}
+
+// WithAutoRestart controls whether a Node.js server started by the client is
+// restarted when it exits unexpectedly. After a restart the client replays the
+// New, SetAuthToken, NewLitContractsClient and Connect calls made so far.
+// Enabled by default.
+func WithAutoRestart(enabled bool) Option {
+	return func(o *clientOptions) {
+		o.autoRestart = enabled
+	}
+}
+
+// WithRestartBackoff sets the delay before the first restart attempt after a
+// crash and the maximum delay between failed attempts, which doubles after
+// each failure. Defaults to 500ms and 30s.
+func WithRestartBackoff(min, max time.Duration) Option {
+	return func(o *clientOptions) {
+		o.restartMinBackoff = min
+		o.restartMaxBackoff = max
+	}
+}
+
+// WithShutdownGracePeriod sets how long Close waits for the Node.js server to
+// exit after SIGTERM before killing it. Defaults to 5 seconds.
+func WithShutdownGracePeriod(gracePeriod time.Duration) Option {
+	return func(o *clientOptions) {
+		o.gracePeriod = gracePeriod
+	}
+}
+
+// WithOnServerExit registers fn to be called whenever the Node.js server
+// started by the client exits on its own. See NodeServer.OnExit.
+func WithOnServerExit(fn func(*ExitError)) Option {
+	return func(o *clientOptions) {
+		o.onServerExit = fn
+	}
+}
+
+// freePort asks the operating system for a free TCP port on localhost
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find a free port: %w", err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,75 @@
+package lit_go_sdk
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// countingTransport counts the requests sent through it
+type countingTransport struct {
+	requests atomic.Int32
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewLitNodeClient_ReusesRunningServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/handshake" {
+			json.NewEncoder(w).Encode(BridgeInfo{BridgeVersion: BridgeVersion, UID: os.Getuid()})
+			return
+		}
+		w.Write([]byte(`{"ready": true}`))
+	}))
+	defer srv.Close()
+
+	_, portStr, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+
+	transport := &countingTransport{}
+	client, err := NewLitNodeClient(
+		WithPort(port),
+		WithHTTPClient(&http.Client{Transport: transport}),
+		WithNodePath("/nonexistent/node"),
+	)
+	if err != nil {
+		t.Fatalf("NewLitNodeClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if client.server != nil {
+		t.Error("Expected the running server to be reused")
+	}
+	if transport.requests.Load() == 0 {
+		t.Error("Expected the injected HTTP client to be used")
+	}
+}
+
+func TestNewLitNodeClient_BadNodePath(t *testing.T) {
+	_, err := NewLitNodeClient(
+		WithEphemeralPort(),
+		WithNodePath("/nonexistent/node"),
+		WithServerPath("options_test.go"),
+	)
+	if err == nil {
+		t.Fatal("Expected an error when the node executable does not exist")
+	}
+}
+
+func TestFreePort(t *testing.T) {
+	port, err := freePort()
+	if err != nil {
+		t.Fatalf("freePort() error = %v", err)
+	}
+	if port <= 0 {
+		t.Errorf("Expected a positive port, got %d", port)
+	}
+}
@@ -0,0 +1,31 @@
+//go:build !windows
+
+package lit_go_sdk
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup puts the server in its own process group so that any child
+// processes it spawns can be signalled along with it
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// terminateProcess asks the server's process group to shut down
+func terminateProcess(p *os.Process) error {
+	return syscall.Kill(-p.Pid, syscall.SIGTERM)
+}
+
+// killProcessGroup kills the server's process group, including any children
+// that outlived the server
+func killProcessGroup(p *os.Process) error {
+	err := syscall.Kill(-p.Pid, syscall.SIGKILL)
+	if err == syscall.ESRCH {
+		// The whole group is already gone
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,26 @@
+//go:build windows
+
+package lit_go_sdk
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup is a no-op on Windows
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// terminateProcess kills the server, as Windows has no SIGTERM
+func terminateProcess(p *os.Process) error {
+	return p.Kill()
+}
+
+// killProcessGroup kills the server process
+func killProcessGroup(p *os.Process) error {
+	err := p.Kill()
+	if errors.Is(err, os.ErrProcessDone) {
+		return nil
+	}
+	return err
+}
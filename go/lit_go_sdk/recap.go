@@ -0,0 +1,245 @@
+package lit_go_sdk
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// recapURNPrefix starts the SIWE resource holding a ReCap
+const recapURNPrefix = "urn:recap:"
+
+// recapStatement starts the part of the SIWE statement describing a ReCap
+const recapStatement = "I further authorize the stated URI to perform the following actions on my behalf:"
+
+// recapAbility is the ReCap ability, "namespace/name", of a Lit ability
+type recapAbility struct {
+	namespace string
+	name      string
+}
+
+// recapAbilities maps each Lit ability to its ReCap ability, like
+// getRecapNamespaceAndAbility in @lit-protocol/auth-helpers
+var recapAbilities = map[LitAbility]recapAbility{
+	LitAbilityAccessControlConditionDecryption: {"Threshold", "Decryption"},
+	LitAbilityAccessControlConditionSigning:    {"Threshold", "Signing"},
+	LitAbilityPKPSigning:                       {"Threshold", "Signing"},
+	LitAbilityRateLimitIncreaseAuth:            {"Auth", "Auth"},
+	LitAbilityLitActionExecution:               {"Threshold", "Execution"},
+}
+
+// Recap is an EIP-5573 ReCap, the capabilities a SIWE message delegates to
+// the URI it is signed for
+type Recap struct {
+	// Attenuations maps each resource URI, e.g. "lit-litaction://*", to its
+	// abilities, "namespace/name", and the restrictions of each
+	Attenuations map[string]map[string][]map[string]interface{} `json:"att"`
+	// Proofs are the CIDs of the capabilities this one is delegated from
+	Proofs []string `json:"prf"`
+}
+
+// NewRecap returns a ReCap without capabilities
+func NewRecap() *Recap {
+	return &Recap{Attenuations: make(map[string]map[string][]map[string]interface{}), Proofs: []string{}}
+}
+
+// AddAttenuation grants the ability namespace/name over resource, with
+// restriction, or none if it is nil
+func (r *Recap) AddAttenuation(resource, namespace, name string, restriction map[string]interface{}) error {
+	if namespace == "" || strings.Contains(namespace, "/") {
+		return fmt.Errorf("%w: invalid ReCap ability namespace %q", ErrInvalidParams, namespace)
+	}
+	if name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("%w: invalid ReCap ability name %q", ErrInvalidParams, name)
+	}
+	if restriction == nil {
+		restriction = map[string]interface{}{}
+	}
+	if r.Attenuations == nil {
+		r.Attenuations = make(map[string]map[string][]map[string]interface{})
+	}
+	abilities := r.Attenuations[resource]
+	if abilities == nil {
+		abilities = make(map[string][]map[string]interface{})
+		r.Attenuations[resource] = abilities
+	}
+	ability := namespace + "/" + name
+	abilities[ability] = append(abilities[ability], restriction)
+	return nil
+}
+
+// AddResourceAbilityRequest grants every ability over the resource of
+// request, and the ability of request, like addRecapToSiweMessage in
+// @lit-protocol/auth-helpers
+func (r *Recap) AddResourceAbilityRequest(request ResourceAbilityRequest) error {
+	if err := request.Validate(); err != nil {
+		return err
+	}
+	resource := request.Resource.Key()
+	if err := r.AddAttenuation(resource, "*", "*", nil); err != nil {
+		return err
+	}
+	ability := recapAbilities[request.Ability]
+	return r.AddAttenuation(resource, ability.namespace, ability.name, nil)
+}
+
+// ResourceAbilityRequests returns the Lit abilities granted over Lit
+// resources, leaving out wildcard abilities and unknown resources
+func (r *Recap) ResourceAbilityRequests() []ResourceAbilityRequest {
+	var requests []ResourceAbilityRequest
+	for _, key := range sortedKeys(r.Attenuations) {
+		prefix, id, ok := strings.Cut(key, "://")
+		if !ok {
+			continue
+		}
+		resource := LitResource{Resource: id, ResourcePrefix: LitResourcePrefix(prefix)}
+		for _, ability := range resourceAbilities[resource.ResourcePrefix] {
+			recap := recapAbilities[ability]
+			if _, ok := r.Attenuations[key][recap.namespace+"/"+recap.name]; ok {
+				requests = append(requests, resource.Request(ability))
+			}
+		}
+	}
+	return requests
+}
+
+// Statement returns the human readable description of the capabilities added
+// to the statement of the SIWE message
+func (r *Recap) Statement() string {
+	var b strings.Builder
+	b.WriteString(recapStatement)
+	section := 1
+	for _, resource := range sortedKeys(r.Attenuations) {
+		// Names are grouped by namespace, in the order of the sorted abilities
+		var namespaces []string
+		names := make(map[string][]string)
+		for _, ability := range sortedKeys(r.Attenuations[resource]) {
+			namespace, name, _ := strings.Cut(ability, "/")
+			if _, ok := names[namespace]; !ok {
+				namespaces = append(namespaces, namespace)
+			}
+			names[namespace] = append(names[namespace], "'"+name+"'")
+		}
+		for _, namespace := range namespaces {
+			fmt.Fprintf(&b, " (%d) '%s': %s for '%s'.", section, namespace, strings.Join(names[namespace], ", "), resource)
+			section++
+		}
+	}
+	return b.String()
+}
+
+// Encode returns the ReCap as a SIWE resource, urn:recap: followed by the
+// unpadded base64url encoding of its canonical JSON
+func (r *Recap) Encode() (string, error) {
+	recap := *r
+	if recap.Attenuations == nil {
+		recap.Attenuations = map[string]map[string][]map[string]interface{}{}
+	}
+	if recap.Proofs == nil {
+		recap.Proofs = []string{}
+	}
+	// encoding/json sorts map keys, as JSON canonicalization does
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(recap); err != nil {
+		return "", err
+	}
+	return recapURNPrefix + base64.RawURLEncoding.EncodeToString(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), nil
+}
+
+// DecodeRecap parses a ReCap SIWE resource
+func DecodeRecap(urn string) (*Recap, error) {
+	encoded, ok := strings.CutPrefix(urn, recapURNPrefix)
+	if !ok {
+		return nil, fmt.Errorf("%w: ReCap resource does not start with %s", ErrInvalidParams, recapURNPrefix)
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid ReCap encoding: %v", ErrInvalidParams, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var recap Recap
+	if err := decoder.Decode(&recap); err != nil {
+		return nil, fmt.Errorf("%w: invalid ReCap: %v", ErrInvalidParams, err)
+	}
+	if recap.Attenuations == nil {
+		return nil, fmt.Errorf("%w: ReCap without attenuations", ErrInvalidParams)
+	}
+	for resource, abilities := range recap.Attenuations {
+		for ability, restrictions := range abilities {
+			if namespace, name, ok := strings.Cut(ability, "/"); !ok || namespace == "" || name == "" {
+				return nil, fmt.Errorf("%w: invalid ReCap ability %q for %s", ErrInvalidParams, ability, resource)
+			}
+			for i := range restrictions {
+				if restrictions[i] == nil {
+					restrictions[i] = map[string]interface{}{}
+				}
+			}
+		}
+	}
+	if recap.Proofs == nil {
+		recap.Proofs = []string{}
+	}
+	return &recap, nil
+}
+
+// clone returns a copy of the ReCap that can be merged into without changing r
+func (r *Recap) clone() *Recap {
+	c := NewRecap()
+	c.Proofs = append(c.Proofs, r.Proofs...)
+	for resource, abilities := range r.Attenuations {
+		c.Attenuations[resource] = make(map[string][]map[string]interface{}, len(abilities))
+		for ability, restrictions := range abilities {
+			c.Attenuations[resource][ability] = append([]map[string]interface{}(nil), restrictions...)
+		}
+	}
+	return c
+}
+
+// merge adds the capabilities of other, like Recap.merge in siwe-recap
+func (r *Recap) merge(other *Recap) {
+	for _, proof := range other.Proofs {
+		if !containsString(r.Proofs, proof) {
+			r.Proofs = append(r.Proofs, proof)
+		}
+	}
+	for resource, abilities := range other.Attenuations {
+		existing, ok := r.Attenuations[resource]
+		if !ok {
+			r.Attenuations[resource] = abilities
+			continue
+		}
+		for ability, restrictions := range abilities {
+			if onlyEmptyRestrictions(existing[ability]) {
+				existing[ability] = restrictions
+			} else {
+				existing[ability] = append(existing[ability], restrictions...)
+			}
+		}
+	}
+}
+
+// onlyEmptyRestrictions reports whether none of restrictions restricts anything
+func onlyEmptyRestrictions(restrictions []map[string]interface{}) bool {
+	for _, restriction := range restrictions {
+		if len(restriction) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,80 @@
+package lit_go_sdk
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRecap_EncodeDecode(t *testing.T) {
+	recap := NewRecap()
+	if err := recap.AddResourceAbilityRequest(LitRLIResource("1").Request(LitAbilityRateLimitIncreaseAuth)); err != nil {
+		t.Fatalf("AddResourceAbilityRequest() error = %v", err)
+	}
+	restriction := map[string]interface{}{"uses": "10", "delegate_to": []interface{}{"f39fd6e51aad88f6f4ce6ab8827279cfffb92266"}}
+	if err := recap.AddAttenuation("lit-ratelimitincrease://1", "Auth", "Auth", restriction); err != nil {
+		t.Fatalf("AddAttenuation() error = %v", err)
+	}
+
+	encoded, err := recap.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	data, _ := base64.RawURLEncoding.DecodeString(encoded[len(recapURNPrefix):])
+	want := `{"att":{"lit-ratelimitincrease://1":{"*/*":[{}],"Auth/Auth":[{},{"delegate_to":["f39fd6e51aad88f6f4ce6ab8827279cfffb92266"],"uses":"10"}]}},"prf":[]}`
+	if string(data) != want {
+		t.Errorf("Encode() = %s, want %s", data, want)
+	}
+
+	decoded, err := DecodeRecap(encoded)
+	if err != nil {
+		t.Fatalf("DecodeRecap() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, recap) {
+		t.Errorf("DecodeRecap() = %+v, want %+v", decoded, recap)
+	}
+	if reencoded, _ := decoded.Encode(); reencoded != encoded {
+		t.Errorf("Expected the decoded ReCap to encode the same, got %s", reencoded)
+	}
+	if want := "I further authorize the stated URI to perform the following actions on my behalf: (1) '*': '*' for 'lit-ratelimitincrease://1'. (2) 'Auth': 'Auth' for 'lit-ratelimitincrease://1'."; recap.Statement() != want {
+		t.Errorf("Statement() = %s, want %s", recap.Statement(), want)
+	}
+	if requests := decoded.ResourceAbilityRequests(); !reflect.DeepEqual(requests, []ResourceAbilityRequest{LitRLIResource("1").Request(LitAbilityRateLimitIncreaseAuth)}) {
+		t.Errorf("ResourceAbilityRequests() = %v", requests)
+	}
+}
+
+func TestRecap_DecodeNumbers(t *testing.T) {
+	// Restrictions keep their numbers as written
+	payload := `{"att":{"https://example.com":{"crud/read":[{"max":1e3}]}},"prf":["bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi"]}`
+	urn := recapURNPrefix + base64.RawURLEncoding.EncodeToString([]byte(payload))
+	recap, err := DecodeRecap(urn)
+	if err != nil {
+		t.Fatalf("DecodeRecap() error = %v", err)
+	}
+	if max := recap.Attenuations["https://example.com"]["crud/read"][0]["max"]; max != json.Number("1e3") {
+		t.Errorf("max = %#v, want 1e3", max)
+	}
+	if encoded, _ := recap.Encode(); encoded != urn {
+		t.Errorf("Encode() = %s, want %s", encoded, urn)
+	}
+}
+
+func TestDecodeRecap_Invalid(t *testing.T) {
+	for name, urn := range map[string]string{
+		"prefix":       "urn:other:e30",
+		"base64":       recapURNPrefix + "!!",
+		"json":         recapURNPrefix + base64.RawURLEncoding.EncodeToString([]byte(`[`)),
+		"no att":       recapURNPrefix + base64.RawURLEncoding.EncodeToString([]byte(`{"prf":[]}`)),
+		"bad ability":  recapURNPrefix + base64.RawURLEncoding.EncodeToString([]byte(`{"att":{"x://y":{"read":[{}]}},"prf":[]}`)),
+		"bad ability2": recapURNPrefix + base64.RawURLEncoding.EncodeToString([]byte(`{"att":{"x://y":{"/read":[{}]}},"prf":[]}`)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := DecodeRecap(urn); !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("DecodeRecap() error = %v, want ErrInvalidParams", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package lit_go_sdk
+
+import "fmt"
+
+// LitResourcePrefix is the kind of a Lit resource
+type LitResourcePrefix string
+
+const (
+	// LitResourcePrefixAccessControlCondition is the prefix of data encrypted
+	// under access control conditions
+	LitResourcePrefixAccessControlCondition LitResourcePrefix = "lit-accesscontrolcondition"
+	// LitResourcePrefixPKP is the prefix of PKPs
+	LitResourcePrefixPKP LitResourcePrefix = "lit-pkp"
+	// LitResourcePrefixRLI is the prefix of Rate Limit Increase NFTs
+	LitResourcePrefixRLI LitResourcePrefix = "lit-ratelimitincrease"
+	// LitResourcePrefixLitAction is the prefix of Lit Actions
+	LitResourcePrefixLitAction LitResourcePrefix = "lit-litaction"
+)
+
+// LitAbility is something a session can be allowed to do with a Lit resource
+type LitAbility string
+
+const (
+	// LitAbilityAccessControlConditionDecryption allows decrypting data
+	// encrypted under access control conditions
+	LitAbilityAccessControlConditionDecryption LitAbility = "access-control-condition-decryption"
+	// LitAbilityAccessControlConditionSigning allows signing with access
+	// control conditions, e.g. for JWTs
+	LitAbilityAccessControlConditionSigning LitAbility = "access-control-condition-signing"
+	// LitAbilityPKPSigning allows signing with a PKP
+	LitAbilityPKPSigning LitAbility = "pkp-signing"
+	// LitAbilityRateLimitIncreaseAuth allows using the capacity of a Rate
+	// Limit Increase NFT
+	LitAbilityRateLimitIncreaseAuth LitAbility = "rate-limit-increase-auth"
+	// LitAbilityLitActionExecution allows executing a Lit Action
+	LitAbilityLitActionExecution LitAbility = "lit-action-execution"
+)
+
+// resourceAbilities are the abilities allowed for each kind of resource
+var resourceAbilities = map[LitResourcePrefix][]LitAbility{
+	LitResourcePrefixAccessControlCondition: {LitAbilityAccessControlConditionDecryption, LitAbilityAccessControlConditionSigning},
+	LitResourcePrefixPKP:                    {LitAbilityPKPSigning},
+	LitResourcePrefixRLI:                    {LitAbilityRateLimitIncreaseAuth},
+	LitResourcePrefixLitAction:              {LitAbilityLitActionExecution},
+}
+
+// LitResource identifies a Lit resource, e.g. the Lit Action with a given IPFS
+// CID, or all of them with the wildcard "*"
+type LitResource struct {
+	// Resource is the resource ID or "*"
+	Resource string `json:"resource"`
+	// ResourcePrefix is the kind of resource
+	ResourcePrefix LitResourcePrefix `json:"resourcePrefix"`
+}
+
+// LitActionResource returns the Lit Action with the IPFS CID cid, or every
+// Lit Action if cid is "*"
+func LitActionResource(cid string) LitResource {
+	return LitResource{Resource: cid, ResourcePrefix: LitResourcePrefixLitAction}
+}
+
+// LitPKPResource returns the PKP with the token ID tokenID, or every PKP if
+// tokenID is "*"
+func LitPKPResource(tokenID string) LitResource {
+	return LitResource{Resource: tokenID, ResourcePrefix: LitResourcePrefixPKP}
+}
+
+// LitAccessControlConditionResource returns the data encrypted under the
+// access control conditions with the hash hash, or all such data if hash is "*"
+func LitAccessControlConditionResource(hash string) LitResource {
+	return LitResource{Resource: hash, ResourcePrefix: LitResourcePrefixAccessControlCondition}
+}
+
+// LitRLIResource returns the Rate Limit Increase NFT with the token ID
+// tokenID, or every one if tokenID is "*"
+func LitRLIResource(tokenID string) LitResource {
+	return LitResource{Resource: tokenID, ResourcePrefix: LitResourcePrefixRLI}
+}
+
+// Key returns the URI of the resource, e.g. lit-litaction://* for every Lit
+// Action
+func (r LitResource) Key() string {
+	return string(r.ResourcePrefix) + "://" + r.Resource
+}
+
+// Request returns a request for ability over the resource
+func (r LitResource) Request(ability LitAbility) ResourceAbilityRequest {
+	return ResourceAbilityRequest{Resource: r, Ability: ability}
+}
+
+// ResourceAbilityRequest is an ability over a Lit resource
+type ResourceAbilityRequest struct {
+	Resource LitResource `json:"resource"`
+	Ability  LitAbility  `json:"ability"`
+}
+
+// Validate checks that the resource is of a known kind and that the ability
+// is allowed for it. The error wraps ErrInvalidParams.
+func (r ResourceAbilityRequest) Validate() error {
+	abilities, ok := resourceAbilities[r.Resource.ResourcePrefix]
+	if !ok {
+		return fmt.Errorf("%w: unknown resource prefix %q", ErrInvalidParams, r.Resource.ResourcePrefix)
+	}
+	if r.Resource.Resource == "" {
+		return fmt.Errorf("%w: %s resource without an ID, use \"*\" for all of them", ErrInvalidParams, r.Resource.ResourcePrefix)
+	}
+	for _, ability := range abilities {
+		if r.Ability == ability {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: ability %q is not allowed for %s resources", ErrInvalidParams, r.Ability, r.Resource.ResourcePrefix)
+}
+
+// validateResourceAbilityRequests validates each request
+func validateResourceAbilityRequests(requests []ResourceAbilityRequest) error {
+	for _, request := range requests {
+		if err := request.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
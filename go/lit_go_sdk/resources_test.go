@@ -0,0 +1,64 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestResourceAbilityRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		request ResourceAbilityRequest
+		wantErr bool
+	}{
+		{"lit action", LitActionResource("QmHash").Request(LitAbilityLitActionExecution), false},
+		{"pkp", LitPKPResource("*").Request(LitAbilityPKPSigning), false},
+		{"acc decryption", LitAccessControlConditionResource("abc/def").Request(LitAbilityAccessControlConditionDecryption), false},
+		{"acc signing", LitAccessControlConditionResource("*").Request(LitAbilityAccessControlConditionSigning), false},
+		{"rli", LitRLIResource("42").Request(LitAbilityRateLimitIncreaseAuth), false},
+		{"wrong ability", LitPKPResource("*").Request(LitAbilityLitActionExecution), true},
+		{"unknown ability", LitActionResource("*").Request("lit-action-signing"), true},
+		{"unknown prefix", ResourceAbilityRequest{Resource: LitResource{Resource: "*", ResourcePrefix: "lit-unknown"}, Ability: LitAbilityPKPSigning}, true},
+		{"no resource", LitActionResource("").Request(LitAbilityLitActionExecution), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.request.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("Expected errors.Is(err, ErrInvalidParams), got %v", err)
+			}
+		})
+	}
+}
+
+func TestResourceAbilityRequest_JSON(t *testing.T) {
+	data, err := json.Marshal(LitActionResource("*").Request(LitAbilityLitActionExecution))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `{"resource":{"resource":"*","resourcePrefix":"lit-litaction"},"ability":"lit-action-execution"}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
+
+func TestGetSessionSigsContext_InvalidRequestNotSent(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Expected no request to be sent")
+	}))
+
+	_, err := client.GetSessionSigsContext(context.Background(), SessionSigsParams{
+		Chain:                   "ethereum",
+		ResourceAbilityRequests: []ResourceAbilityRequest{LitPKPResource("*").Request(LitAbilityLitActionExecution)},
+	})
+	if !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams, got %v", err)
+	}
+}
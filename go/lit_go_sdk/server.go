@@ -1,13 +1,17 @@
 package lit_go_sdk
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
+	"strings"
 	"sync"
+	"syscall"
+	"time"
 )
 
 const maxBufferSize = 32 * 1024 * 1024 // 32MB in bytes
@@ -78,38 +82,119 @@ func (b *rotatingBuffer) String() string {
 	return string(result)
 }
 
+// bridgeSecretEnv is the environment variable the server reads the shared
+// secret from. Requests must carry it as a bearer token in the Authorization header.
+const bridgeSecretEnv = "LIT_BRIDGE_SECRET"
+
+// newBridgeSecret generates a random secret for a server launch
+func newBridgeSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate server secret: %w", err)
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// exitLogLines is the number of log lines captured in an ExitError
+const exitLogLines = 50
+
+// ExitError describes a Node.js server process that exited on its own
+type ExitError struct {
+	// ExitCode is the process exit code, or -1 if it was killed by a signal
+	ExitCode int
+	// Signal is the name of the signal that killed the process, if any
+	Signal string
+	// Logs holds the last lines the server logged before it exited
+	Logs []string
+	// Err is the error returned when waiting for the process, if any
+	Err error
+}
+
+// Error implements the error interface
+func (e *ExitError) Error() string {
+	if e.Signal != "" {
+		return fmt.Sprintf("Node.js server killed by signal: %s", e.Signal)
+	}
+	return fmt.Sprintf("Node.js server exited with code %d", e.ExitCode)
+}
+
+// Unwrap returns the underlying error
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// newExitError describes the exit of a process that has been waited for
+func newExitError(state *os.ProcessState, err error, logs []string) *ExitError {
+	exitErr := &ExitError{
+		ExitCode: -1,
+		Logs:     logs,
+		Err:      err,
+	}
+	if state != nil {
+		exitErr.ExitCode = state.ExitCode()
+		if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			exitErr.Signal = status.Signal().String()
+		}
+	}
+	return exitErr
+}
+
 // NodeServer manages the Node.js server process
 type NodeServer struct {
-	port int
-	cmd  *exec.Cmd
-	logs *rotatingBuffer
+	port       int
+	nodePath   string
+	serverPath string
+	cacheDir   string
+	env        []string
+	secret     string
+	socketPath string
+	// gracePeriod is how long Stop waits after SIGTERM before killing the process
+	gracePeriod time.Duration
+	dir         string
+	logs        *rotatingBuffer
+
+	mu      sync.Mutex
+	cmd     *exec.Cmd
+	exited  chan struct{} // closed when the process in cmd exits
+	exitErr *ExitError    // set when the process exits without Stop being called
+	onCrash func()        // called when the process exits without Stop being called
+	onExit  []func(*ExitError)
 }
 
 // NewNodeServer creates a new instance of NodeServer
 func NewNodeServer(port int) *NodeServer {
 	return &NodeServer{
-		port: port,
-		logs: newRotatingBuffer(),
+		port:        port,
+		nodePath:    defaultNodePath,
+		gracePeriod: defaultShutdownGracePeriod,
+		logs:        newRotatingBuffer(),
 	}
 }
 
-// Start starts the Node.js server process
+// Start starts the Node.js server process. It can be called again to restart
+// the server after the process has exited.
 func (s *NodeServer) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if s.cmd != nil {
 		return nil
 	}
 
-	// Get the directory where the current Go file is located
-	_, filename, _, ok := runtime.Caller(0)
-	if !ok {
-		return fmt.Errorf("failed to get current file path")
+	serverPath := s.serverPath
+	if serverPath == "" {
+		// Extract the embedded bundle, checking its integrity before node runs it
+		var err error
+		if serverPath, err = extractBundledServer(s.cacheDir); err != nil {
+			return fmt.Errorf("failed to prepare bundled server: %w", err)
+		}
+	} else if _, err := os.Stat(serverPath); os.IsNotExist(err) {
+		return fmt.Errorf("server script not found at %s", serverPath)
 	}
-	sdkDir := filepath.Dir(filename)
 
-	// Path to the bundled server
-	serverPath := filepath.Join(sdkDir, "bundled_server.js")
-	if _, err := os.Stat(serverPath); os.IsNotExist(err) {
-		return fmt.Errorf("bundled server not found at %s: this is likely an installation issue", serverPath)
+	dir := s.dir
+	if dir == "" {
+		dir = filepath.Dir(serverPath)
 	}
 
 	// Create a multiwriter to write to both stderr and our rotating buffer
@@ -124,36 +209,126 @@ func (s *NodeServer) Start() error {
 	multiWriter := io.MultiWriter(writers...)
 
 	// Prepare the command
-	s.cmd = exec.Command("node", serverPath)
-	s.cmd.Stdout = multiWriter
-	s.cmd.Stderr = multiWriter
-	s.cmd.Env = append(os.Environ(), fmt.Sprintf("PORT=%d", s.port))
-	s.cmd.Dir = sdkDir
+	cmd := exec.Command(s.nodePath, serverPath)
+	cmd.Stdout = multiWriter
+	cmd.Stderr = multiWriter
+	cmd.Env = append(append(os.Environ(), s.env...), fmt.Sprintf("PORT=%d", s.port))
+	if s.secret != "" {
+		cmd.Env = append(cmd.Env, bridgeSecretEnv+"="+s.secret)
+	}
+	if s.socketPath != "" {
+		// A socket left behind by a crashed process would make listen fail
+		if err := os.Remove(s.socketPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stale socket: %w", err)
+		}
+		cmd.Env = append(cmd.Env, bridgeSocketEnv+"="+s.socketPath)
+	}
+	cmd.Dir = dir
+	setProcessGroup(cmd)
 
 	// Start the process
-	if err := s.cmd.Start(); err != nil {
+	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("failed to start Node.js server: %w", err)
 	}
 
+	exited := make(chan struct{})
+	s.cmd = cmd
+	s.exited = exited
+	s.exitErr = nil
+
 	// Monitor the process in a goroutine
 	go func() {
-		err := s.cmd.Wait()
-		if err != nil {
-			fmt.Printf("\n=== Node.js Server Crashed ===\nError: %v\n", err)
+		err := cmd.Wait()
+
+		s.mu.Lock()
+		// Stop clears s.cmd before killing the process, so a process that is
+		// still current here has exited on its own
+		crashed := s.cmd == cmd
+		var exitErr *ExitError
+		if crashed {
+			s.cmd = nil
+			exitErr = newExitError(cmd.ProcessState, err, s.lastLogLines(exitLogLines))
+			s.exitErr = exitErr
+		}
+		onCrash := s.onCrash
+		onExit := append([]func(*ExitError){}, s.onExit...)
+		s.mu.Unlock()
+
+		if !crashed {
+			close(exited)
+			return
+		}
+
+		fmt.Fprintf(s.logs, "\n=== Node.js Server Crashed ===\nError: %v\n", exitErr)
+		if onCrash != nil {
+			onCrash()
+		}
+		close(exited)
+		for _, fn := range onExit {
+			fn(exitErr)
 		}
 	}()
 
 	return nil
 }
 
-// Stop stops the Node.js server process
+// Done returns a channel that is closed when the running server process exits,
+// whether it crashed or was stopped. After a restart, Done returns a new
+// channel for the new process. It returns nil if the server was never started.
+func (s *NodeServer) Done() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.exited
+}
+
+// Err returns an *ExitError describing how the server process exited on its
+// own. It returns nil while the process is running and after Stop.
+func (s *NodeServer) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.exitErr == nil {
+		return nil
+	}
+	return s.exitErr
+}
+
+// OnExit registers fn to be called when the server process exits on its own,
+// after Done is closed. It is not called when the server is stopped with Stop.
+func (s *NodeServer) OnExit(fn func(*ExitError)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onExit = append(s.onExit, fn)
+}
+
+// Stop stops the Node.js server process. It sends SIGTERM, waits up to the
+// shutdown grace period for the process to exit and then kills its whole
+// process group, so children spawned by the server do not outlive it.
 func (s *NodeServer) Stop() error {
-	if s.cmd != nil && s.cmd.Process != nil {
-		if err := s.cmd.Process.Kill(); err != nil {
-			return fmt.Errorf("failed to kill server process: %w", err)
-		}
-		s.cmd = nil
+	s.mu.Lock()
+	cmd := s.cmd
+	exited := s.exited
+	s.cmd = nil
+	s.mu.Unlock()
+
+	if cmd == nil || cmd.Process == nil {
+		return nil
+	}
+
+	if err := terminateProcess(cmd.Process); err != nil {
+		// The process may have exited already, in which case killing the group
+		// below still cleans up its children
+		fmt.Fprintf(s.logs, "\n=== Failed to terminate Node.js Server ===\nError: %v\n", err)
+	}
+
+	select {
+	case <-exited:
+	case <-time.After(s.gracePeriod):
+	}
+
+	if err := killProcessGroup(cmd.Process); err != nil {
+		return fmt.Errorf("failed to kill server process: %w", err)
 	}
+	<-exited
 	return nil
 }
 
@@ -161,3 +336,12 @@ func (s *NodeServer) Stop() error {
 func (s *NodeServer) GetLogs() string {
 	return s.logs.String()
 }
+
+// lastLogLines returns up to the last n lines of the log buffer
+func (s *NodeServer) lastLogLines(n int) []string {
+	logLines := strings.Split(strings.TrimRight(s.logs.String(), "\n"), "\n")
+	if len(logLines) > n {
+		logLines = logLines[len(logLines)-n:]
+	}
+	return logLines
+}
@@ -0,0 +1,60 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// processAlive reports whether pid is a running (not zombie) process
+func processAlive(pid int) bool {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return false
+	}
+	// The state follows the parenthesised command name
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return len(fields) > 0 && fields[0] != "Z"
+}
+
+func TestNodeServer_StopKillsProcessGroup(t *testing.T) {
+	const gracePeriod = 200 * time.Millisecond
+	opts := append(fakeBridgeOptions(t),
+		WithAutoRestart(false),
+		WithShutdownGracePeriod(gracePeriod),
+		WithEnv(fakeBridgeIgnoreTermEnv+"=1"),
+	)
+	client, err := NewLitNodeClient(opts...)
+	if err != nil {
+		t.Fatalf("NewLitNodeClient() error = %v", err)
+	}
+
+	result, err := client.send(context.Background(), "/spawnChild", nil)
+	if err != nil {
+		t.Fatalf("Failed to spawn child process: %v", err)
+	}
+	childPid := int(result["pid"].(float64))
+	serverPid := client.Server().cmd.Process.Pid
+
+	start := time.Now()
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < gracePeriod {
+		t.Errorf("Close() returned after %v, expected it to wait out the %v grace period", elapsed, gracePeriod)
+	}
+
+	if processAlive(serverPid) {
+		t.Error("Expected the server process to be gone after Close")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for processAlive(childPid) {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the server's child process to be killed with its process group")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
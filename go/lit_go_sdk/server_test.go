@@ -0,0 +1,137 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNodeServer_ExitNotification(t *testing.T) {
+	exits := make(chan *ExitError, 1)
+	opts := append(fakeBridgeOptions(t),
+		WithAutoRestart(false),
+		WithOnServerExit(func(err *ExitError) { exits <- err }),
+	)
+	client, err := NewLitNodeClient(opts...)
+	if err != nil {
+		t.Fatalf("NewLitNodeClient() error = %v", err)
+	}
+	defer client.Close()
+
+	server := client.Server()
+	if server == nil {
+		t.Fatal("Expected the client to have started a server")
+	}
+	if err := server.Err(); err != nil {
+		t.Fatalf("Err() = %v while the server is running", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client.send(ctx, "/crash", nil)
+
+	select {
+	case <-server.Done():
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for the server to exit")
+	}
+
+	var exitErr *ExitError
+	if !errors.As(server.Err(), &exitErr) {
+		t.Fatalf("Err() = %v, want *ExitError", server.Err())
+	}
+	if exitErr.ExitCode != 3 {
+		t.Errorf("ExitCode = %d, want 3", exitErr.ExitCode)
+	}
+	if !strings.Contains(strings.Join(exitErr.Logs, "\n"), "fake bridge crashing") {
+		t.Errorf("Expected the crash log line in Logs, got %q", exitErr.Logs)
+	}
+
+	select {
+	case got := <-exits:
+		if got != exitErr {
+			t.Errorf("OnExit got %v, want %v", got, exitErr)
+		}
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for the OnExit callback")
+	}
+}
+
+func TestNodeServer_StopIsNotACrash(t *testing.T) {
+	opts := append(fakeBridgeOptions(t),
+		WithAutoRestart(false),
+		WithOnServerExit(func(err *ExitError) { t.Errorf("OnExit called after Stop with %v", err) }),
+	)
+	client, err := NewLitNodeClient(opts...)
+	if err != nil {
+		t.Fatalf("NewLitNodeClient() error = %v", err)
+	}
+
+	server := client.Server()
+	done := server.Done()
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for the server to stop")
+	}
+	if err := server.Err(); err != nil {
+		t.Errorf("Err() = %v after Stop, want nil", err)
+	}
+}
+
+func TestLitNodeClient_CloseIsIdempotent(t *testing.T) {
+	client, err := NewLitNodeClient(fakeBridgeOptions(t)...)
+	if err != nil {
+		t.Fatalf("NewLitNodeClient() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- client.Close()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+	}
+	if err := client.Close(); err != nil {
+		t.Errorf("Close() after close error = %v", err)
+	}
+}
+
+func TestLitNodeClient_BridgeSecret(t *testing.T) {
+	client, err := NewLitNodeClient(fakeBridgeOptions(t)...)
+	if err != nil {
+		t.Fatalf("NewLitNodeClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if client.secret == "" {
+		t.Fatal("Expected a secret to be generated for the server")
+	}
+	if _, err := client.ConnectContext(context.Background()); err != nil {
+		t.Fatalf("ConnectContext() error = %v", err)
+	}
+
+	// Another client on the same port without the secret is rejected
+	intruder := &LitNodeClient{port: client.port, httpClient: client.httpClient}
+	_, err = intruder.ConnectContext(context.Background())
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("ConnectContext() without the secret error = %v, want ErrUnauthorized", err)
+	}
+}
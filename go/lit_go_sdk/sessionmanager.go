@@ -0,0 +1,269 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultSessionTTL is how long the session sigs a SessionManager gets are valid for
+	defaultSessionTTL = 10 * time.Minute
+	// defaultSessionRefreshMargin is how long before expiring session sigs are refreshed
+	defaultSessionRefreshMargin = time.Minute
+	// defaultSessionChain is the chain session sigs are requested for when the operation has none
+	defaultSessionChain = ChainEthereum
+)
+
+// sessionExpirationFormat is the ISO 8601 format the JS SDK uses for expirations
+const sessionExpirationFormat = "2006-01-02T15:04:05.000Z"
+
+// The abilities ExecuteJs, PKPSign and DecryptString need over every
+// resource of their kind, requested when session sigs are obtained for them
+var (
+	litActionExecutionRequest               = LitActionResource("*").Request(LitAbilityLitActionExecution)
+	pkpSigningRequest                       = LitPKPResource("*").Request(LitAbilityPKPSigning)
+	accessControlConditionDecryptionRequest = LitAccessControlConditionResource("*").Request(LitAbilityAccessControlConditionDecryption)
+)
+
+// SessionOption configures a SessionManager
+type SessionOption func(*SessionManager)
+
+// WithSessionTTL sets how long the session sigs requested are valid for.
+// Defaults to 10 minutes.
+func WithSessionTTL(ttl time.Duration) SessionOption {
+	return func(m *SessionManager) {
+		m.ttl = ttl
+	}
+}
+
+// WithSessionRefreshMargin sets how long before they expire cached session
+// sigs are replaced by new ones. Defaults to 1 minute.
+func WithSessionRefreshMargin(margin time.Duration) SessionOption {
+	return func(m *SessionManager) {
+		m.margin = margin
+	}
+}
+
+// WithSessionChain sets the chain session sigs are requested for by the
+// operations that do not name one, ExecuteJs and PKPSign. Defaults to ethereum.
+func WithSessionChain(chain Chain) SessionOption {
+	return func(m *SessionManager) {
+		m.chain = chain
+	}
+}
+
+// SessionManager obtains session sigs with GetSessionSigs and caches them by
+// chain and set of resource ability requests. Cached sigs are returned until
+// they are about to expire and are then replaced by new ones, with concurrent
+// callers sharing a single GetSessionSigs call. It is safe for concurrent use.
+type SessionManager struct {
+	auth   Authenticator
+	ttl    time.Duration
+	margin time.Duration
+	chain  Chain
+	now    func() time.Time
+
+	mu       sync.Mutex
+	cache    map[string]cachedSessionSigs
+	inflight map[string]*sessionRefresh
+	// generation is incremented by Invalidate, so refreshes started before
+	// it do not cache their result
+	generation uint64
+}
+
+// cachedSessionSigs are session sigs and when they must be replaced
+type cachedSessionSigs struct {
+	sessionSigs SessionSigs
+	expiresAt   time.Time
+}
+
+// sessionRefresh is a GetSessionSigs call other callers can wait for
+type sessionRefresh struct {
+	done        chan struct{}
+	sessionSigs SessionSigs
+	err         error
+}
+
+// NewSessionManager returns a SessionManager getting session sigs from auth,
+// usually a LitNodeClient with an auth token set. The refresh margin must be
+// shorter than the TTL, or every call would refresh; the error wraps
+// ErrInvalidParams.
+func NewSessionManager(auth Authenticator, opts ...SessionOption) (*SessionManager, error) {
+	m := &SessionManager{
+		auth:     auth,
+		ttl:      defaultSessionTTL,
+		margin:   defaultSessionRefreshMargin,
+		chain:    defaultSessionChain,
+		now:      time.Now,
+		cache:    make(map[string]cachedSessionSigs),
+		inflight: make(map[string]*sessionRefresh),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.ttl <= 0 || m.margin < 0 {
+		return nil, fmt.Errorf("%w: session TTL must be positive and refresh margin not negative", ErrInvalidParams)
+	}
+	if m.margin >= m.ttl {
+		return nil, fmt.Errorf("%w: session refresh margin %v must be shorter than the TTL %v", ErrInvalidParams, m.margin, m.ttl)
+	}
+	return m, nil
+}
+
+// SessionSigs returns session sigs for chain granting resourceAbilityRequests,
+// from the cache if they are not about to expire
+func (m *SessionManager) SessionSigs(ctx context.Context, chain Chain, resourceAbilityRequests []ResourceAbilityRequest) (SessionSigs, error) {
+	key, err := sessionCacheKey(chain, resourceAbilityRequests)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		m.mu.Lock()
+		if cached, ok := m.cache[key]; ok && m.now().Before(cached.expiresAt.Add(-m.margin)) {
+			m.mu.Unlock()
+			return cached.sessionSigs, nil
+		}
+		if refresh, ok := m.inflight[key]; ok {
+			m.mu.Unlock()
+			select {
+			case <-refresh.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			// The caller that started the refresh gave up, try again with our context
+			if isContextError(refresh.err) && ctx.Err() == nil {
+				continue
+			}
+			return refresh.sessionSigs, refresh.err
+		}
+		refresh := &sessionRefresh{done: make(chan struct{})}
+		m.inflight[key] = refresh
+		generation := m.generation
+		m.mu.Unlock()
+
+		cached, err := m.getSessionSigs(ctx, chain, resourceAbilityRequests)
+
+		m.mu.Lock()
+		if m.inflight[key] == refresh {
+			delete(m.inflight, key)
+		}
+		// Sigs requested before an Invalidate may be stale
+		if err == nil && m.generation == generation {
+			m.cache[key] = cached
+		}
+		m.mu.Unlock()
+		refresh.sessionSigs, refresh.err = cached.sessionSigs, err
+		close(refresh.done)
+		return cached.sessionSigs, err
+	}
+}
+
+// Invalidate drops every cached session sig, e.g. after the wallet changed.
+// Refreshes already in flight are not cached, and later callers do not wait
+// for them.
+func (m *SessionManager) Invalidate() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cache = make(map[string]cachedSessionSigs)
+	m.inflight = make(map[string]*sessionRefresh)
+	m.generation++
+}
+
+// invalidate drops the session sigs cached under key if they are still
+// sessionSigs, not ones refreshed since
+func (m *SessionManager) invalidate(key string, sessionSigs SessionSigs) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cached, ok := m.cache[key]; ok && sameSessionSigs(cached.sessionSigs, sessionSigs) {
+		delete(m.cache, key)
+	}
+}
+
+// sameSessionSigs reports whether a and b hold the same signatures
+func sameSessionSigs(a, b SessionSigs) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for node, sig := range a {
+		if b[node].Sig != sig.Sig {
+			return false
+		}
+	}
+	return true
+}
+
+// getSessionSigs asks for new session sigs
+func (m *SessionManager) getSessionSigs(ctx context.Context, chain Chain, resourceAbilityRequests []ResourceAbilityRequest) (cachedSessionSigs, error) {
+	expiration := m.now().Add(m.ttl).UTC()
+	sessionSigs, err := m.auth.GetSessionSigsContext(ctx, SessionSigsParams{
+		Chain:                   chain,
+		Expiration:              expiration.Format(sessionExpirationFormat),
+		ResourceAbilityRequests: resourceAbilityRequests,
+	})
+	if err != nil {
+		return cachedSessionSigs{}, err
+	}
+	// The nodes may grant less time than asked for
+	if expiresAt, err := sessionSigs.ExpiresAt(); err == nil && expiresAt.Before(expiration) {
+		expiration = expiresAt
+	}
+	return cachedSessionSigs{sessionSigs: sessionSigs, expiresAt: expiration}, nil
+}
+
+// sessionCacheKey identifies a chain and a set of resource ability requests,
+// regardless of the order of the requests
+func sessionCacheKey(chain Chain, resourceAbilityRequests []ResourceAbilityRequest) (string, error) {
+	parts := make([]string, 0, len(resourceAbilityRequests))
+	for _, request := range resourceAbilityRequests {
+		data, err := json.Marshal(request)
+		if err != nil {
+			return "", fmt.Errorf("%w: resource ability request: %v", ErrInvalidParams, err)
+		}
+		parts = append(parts, string(data))
+	}
+	sort.Strings(parts)
+	return string(chain) + "\n" + strings.Join(parts, "\n"), nil
+}
+
+// isContextError reports whether err is the error of a cancelled or expired context
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// Sessions returns the SessionManager set up with WithAutoSessionSigs, or nil
+func (c *LitNodeClient) Sessions() *SessionManager {
+	return c.sessions
+}
+
+// postWithSessionSigs is post for operations taking session sigs. When the
+// caller passed none, *sessionSigs is filled in from the client's
+// SessionManager, if it has one, and dropped from its cache if the nodes
+// reject them.
+func (c *LitNodeClient) postWithSessionSigs(ctx context.Context, endpoint string, params interface{}, sessionSigs *SessionSigs, chain Chain, request ResourceAbilityRequest) (map[string]interface{}, error) {
+	if *sessionSigs != nil || c.sessions == nil {
+		return c.post(ctx, endpoint, params)
+	}
+	if chain == "" {
+		chain = c.sessions.chain
+	}
+	requests := []ResourceAbilityRequest{request}
+	key, err := sessionCacheKey(chain, requests)
+	if err != nil {
+		return nil, err
+	}
+	if *sessionSigs, err = c.sessions.SessionSigs(ctx, chain, requests); err != nil {
+		return nil, err
+	}
+	result, err := c.post(ctx, endpoint, params)
+	if errors.Is(err, ErrAuthExpired) {
+		c.sessions.invalidate(key, *sessionSigs)
+	}
+	return result, err
+}
@@ -0,0 +1,311 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// stubAuthenticator answers GetSessionSigs with session sigs expiring when asked
+type stubAuthenticator struct {
+	Authenticator
+	calls   int32
+	release chan struct{}
+}
+
+func (a *stubAuthenticator) GetSessionSigsContext(ctx context.Context, params SessionSigsParams) (SessionSigs, error) {
+	atomic.AddInt32(&a.calls, 1)
+	if a.release != nil {
+		<-a.release
+	}
+	return SessionSigsFromResult(sessionSigsResponse(params.Expiration))
+}
+
+// sessionSigsResponse is a GetSessionSigs result expiring at expiration
+func sessionSigsResponse(expiration string) map[string]interface{} {
+	signedMessage, _ := json.Marshal(map[string]interface{}{"expiration": expiration})
+	return map[string]interface{}{
+		"sessionSigs": map[string]interface{}{
+			"https://node-1:443": map[string]interface{}{"sig": "aa", "signedMessage": string(signedMessage)},
+		},
+	}
+}
+
+func TestSessionManager_Caches(t *testing.T) {
+	auth := &stubAuthenticator{}
+	m, _ := NewSessionManager(auth)
+	ctx := context.Background()
+
+	requests := []ResourceAbilityRequest{litActionExecutionRequest, pkpSigningRequest}
+	first, err := m.SessionSigs(ctx, "ethereum", requests)
+	if err != nil {
+		t.Fatalf("SessionSigs() error = %v", err)
+	}
+	// The same set of requests in another order is served from the cache
+	second, err := m.SessionSigs(ctx, "ethereum", []ResourceAbilityRequest{pkpSigningRequest, litActionExecutionRequest})
+	if err != nil {
+		t.Fatalf("SessionSigs() error = %v", err)
+	}
+	if calls := atomic.LoadInt32(&auth.calls); calls != 1 {
+		t.Errorf("Expected 1 GetSessionSigs call, got %d", calls)
+	}
+	if first["https://node-1:443"].Sig != second["https://node-1:443"].Sig {
+		t.Error("Expected the cached session sigs")
+	}
+
+	if _, err := m.SessionSigs(ctx, "polygon", requests); err != nil {
+		t.Fatalf("SessionSigs() error = %v", err)
+	}
+	if _, err := m.SessionSigs(ctx, "ethereum", requests[:1]); err != nil {
+		t.Fatalf("SessionSigs() error = %v", err)
+	}
+	if calls := atomic.LoadInt32(&auth.calls); calls != 3 {
+		t.Errorf("Expected another chain and request set to get new session sigs, got %d calls", calls)
+	}
+
+	m.Invalidate()
+	if _, err := m.SessionSigs(ctx, "ethereum", requests); err != nil {
+		t.Fatalf("SessionSigs() error = %v", err)
+	}
+	if calls := atomic.LoadInt32(&auth.calls); calls != 4 {
+		t.Errorf("Expected Invalidate to drop the cache, got %d calls", calls)
+	}
+}
+
+func TestSessionManager_RefreshesBeforeExpiration(t *testing.T) {
+	auth := &stubAuthenticator{}
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	m, _ := NewSessionManager(auth, WithSessionTTL(10*time.Minute), WithSessionRefreshMargin(2*time.Minute))
+	m.now = func() time.Time { return now }
+
+	requests := []ResourceAbilityRequest{litActionExecutionRequest}
+	sessionSigs, err := m.SessionSigs(context.Background(), "ethereum", requests)
+	if err != nil {
+		t.Fatalf("SessionSigs() error = %v", err)
+	}
+	if expiresAt, _ := sessionSigs.ExpiresAt(); !expiresAt.Equal(now.Add(10 * time.Minute)) {
+		t.Errorf("ExpiresAt() = %v, want the session TTL from now", expiresAt)
+	}
+
+	now = now.Add(7*time.Minute + 59*time.Second)
+	m.SessionSigs(context.Background(), "ethereum", requests)
+	if calls := atomic.LoadInt32(&auth.calls); calls != 1 {
+		t.Errorf("Expected the session sigs to be cached until the margin, got %d calls", calls)
+	}
+
+	now = now.Add(time.Second)
+	m.SessionSigs(context.Background(), "ethereum", requests)
+	if calls := atomic.LoadInt32(&auth.calls); calls != 2 {
+		t.Errorf("Expected the session sigs to be refreshed within the margin, got %d calls", calls)
+	}
+}
+
+func TestSessionManager_DeduplicatesConcurrentRefreshes(t *testing.T) {
+	auth := &stubAuthenticator{release: make(chan struct{})}
+	m, _ := NewSessionManager(auth)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := m.SessionSigs(context.Background(), "ethereum", []ResourceAbilityRequest{pkpSigningRequest})
+			errs <- err
+		}()
+	}
+	// Let the goroutines pile up on the first refresh
+	time.Sleep(50 * time.Millisecond)
+	close(auth.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("SessionSigs() error = %v", err)
+		}
+	}
+	if calls := atomic.LoadInt32(&auth.calls); calls != 1 {
+		t.Errorf("Expected 1 GetSessionSigs call, got %d", calls)
+	}
+}
+
+func TestSessionManager_InvalidateDuringRefresh(t *testing.T) {
+	auth := &stubAuthenticator{release: make(chan struct{})}
+	m, _ := NewSessionManager(auth)
+	requests := []ResourceAbilityRequest{pkpSigningRequest}
+
+	done := make(chan error)
+	go func() {
+		_, err := m.SessionSigs(context.Background(), "ethereum", requests)
+		done <- err
+	}()
+	for atomic.LoadInt32(&auth.calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// The refresh in flight was started for the old state, so its result
+	// must not be cached
+	m.Invalidate()
+	close(auth.release)
+	if err := <-done; err != nil {
+		t.Fatalf("SessionSigs() error = %v", err)
+	}
+
+	if _, err := m.SessionSigs(context.Background(), "ethereum", requests); err != nil {
+		t.Fatalf("SessionSigs() error = %v", err)
+	}
+	if calls := atomic.LoadInt32(&auth.calls); calls != 2 {
+		t.Errorf("Expected the refresh started before Invalidate not to be cached, got %d calls", calls)
+	}
+}
+
+func TestNewSessionManager_InvalidOptions(t *testing.T) {
+	for name, opts := range map[string][]SessionOption{
+		"margin equal to ttl":  {WithSessionTTL(time.Minute), WithSessionRefreshMargin(time.Minute)},
+		"margin longer":        {WithSessionTTL(time.Minute), WithSessionRefreshMargin(2 * time.Minute)},
+		"zero ttl":             {WithSessionTTL(0)},
+		"negative margin":      {WithSessionRefreshMargin(-time.Second)},
+		"default margin + ttl": {WithSessionTTL(30 * time.Second)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewSessionManager(&stubAuthenticator{}, opts...); !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("NewSessionManager() error = %v, want ErrInvalidParams", err)
+			}
+		})
+	}
+
+	_, err := NewLitNodeClient(WithAutoSessionSigs(WithSessionTTL(time.Minute), WithSessionRefreshMargin(time.Minute)))
+	if !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("NewLitNodeClient() error = %v, want ErrInvalidParams", err)
+	}
+}
+
+func TestExecuteJs_AutoSessionSigs(t *testing.T) {
+	var mu sync.Mutex
+	var sessionSigsCalls int
+	rejectSessionSigs := false
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/litNodeClient/getSessionSigs":
+			sessionSigsCalls++
+			var params SessionSigsParams
+			json.Unmarshal(body, &params)
+			if !strings.Contains(string(body), "lit-action-execution") && !strings.Contains(string(body), "pkp-signing") {
+				t.Errorf("Expected session sigs for lit-action-execution or pkp-signing, got %s", body)
+			}
+			json.NewEncoder(w).Encode(sessionSigsResponse(params.Expiration))
+		case "/litNodeClient/executeJs":
+			if !strings.Contains(string(body), `"https://node-1:443"`) {
+				t.Errorf("Expected the session sigs to be sent, got %s", body)
+			}
+			if rejectSessionSigs {
+				w.WriteHeader(http.StatusInternalServerError)
+				w.Write([]byte(`{"error": {"message": "expired", "code": "InvalidSessionSigs"}}`))
+				return
+			}
+			w.Write([]byte(`{"success": true}`))
+		}
+	}))
+	client.sessions, _ = NewSessionManager(client)
+
+	// Session sigs cached for another operation
+	pkpSessionSigs, err := client.sessions.SessionSigs(context.Background(), ChainEthereum, []ResourceAbilityRequest{pkpSigningRequest})
+	if err != nil {
+		t.Fatalf("SessionSigs() error = %v", err)
+	}
+	sessionSigsCalls = 0
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.ExecuteJs(ExecuteJsParams{Code: "code"}); err != nil {
+			t.Fatalf("ExecuteJs() error = %v", err)
+		}
+	}
+	if sessionSigsCalls != 1 {
+		t.Errorf("Expected the session sigs to be reused, got %d GetSessionSigs calls", sessionSigsCalls)
+	}
+
+	// Session sigs the nodes reject are dropped
+	mu.Lock()
+	rejectSessionSigs = true
+	mu.Unlock()
+	client.ExecuteJs(ExecuteJsParams{Code: "code"})
+	mu.Lock()
+	rejectSessionSigs = false
+	mu.Unlock()
+	if _, err := client.ExecuteJs(ExecuteJsParams{Code: "code"}); err != nil {
+		t.Fatalf("ExecuteJs() error = %v", err)
+	}
+	if sessionSigsCalls != 2 {
+		t.Errorf("Expected new session sigs after a rejection, got %d GetSessionSigs calls", sessionSigsCalls)
+	}
+
+	// Only the rejected session sigs are dropped
+	if _, err := client.sessions.SessionSigs(context.Background(), ChainEthereum, []ResourceAbilityRequest{pkpSigningRequest}); err != nil {
+		t.Fatalf("SessionSigs() error = %v", err)
+	}
+	if sessionSigsCalls != 2 {
+		t.Errorf("Expected the other session sigs %v to stay cached, got %d GetSessionSigs calls", pkpSessionSigs, sessionSigsCalls)
+	}
+}
+
+func TestSetSigner_InvalidatesSessionSigs(t *testing.T) {
+	var sessionSigsCalls int
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/litNodeClient/getSessionSigs":
+			sessionSigsCalls++
+			var params SessionSigsParams
+			json.Unmarshal(body, &params)
+			json.NewEncoder(w).Encode(sessionSigsResponse(params.Expiration))
+		default:
+			w.Write([]byte(`{"success": true}`))
+		}
+	}))
+	client.sessions, _ = NewSessionManager(client)
+
+	first, _ := PrivateKeySignerFromHex(signerTestKey)
+	otherKey, _ := crypto.GenerateKey()
+	other := NewPrivateKeySigner(otherKey)
+	// Each step sets a signer, then gets session sigs for the same requests
+	steps := []struct {
+		name      string
+		setSigner func() error
+		wantCalls int
+	}{
+		{"first signer", func() error { _, err := client.SetSigner(first); return err }, 1},
+		{"same wallet", func() error { _, err := client.SetAuthToken(signerTestKey); return err }, 1},
+		{"other signer", func() error { _, err := client.SetSigner(other); return err }, 2},
+		{"contracts client with a key", func() error {
+			_, err := client.NewLitContractsClient(LitContractsClientConfig{PrivateKey: signerTestKey})
+			return err
+		}, 3},
+		{"contracts client with the current signer", func() error {
+			_, err := client.NewLitContractsClient(LitContractsClientConfig{})
+			return err
+		}, 3},
+	}
+	for _, step := range steps {
+		if err := step.setSigner(); err != nil {
+			t.Fatalf("%s: error = %v", step.name, err)
+		}
+		if _, err := client.sessions.SessionSigs(context.Background(), ChainEthereum, []ResourceAbilityRequest{pkpSigningRequest}); err != nil {
+			t.Fatalf("%s: SessionSigs() error = %v", step.name, err)
+		}
+		if sessionSigsCalls != step.wantCalls {
+			t.Errorf("%s: got %d GetSessionSigs calls, want %d", step.name, sessionSigsCalls, step.wantCalls)
+		}
+	}
+}
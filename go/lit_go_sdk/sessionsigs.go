@@ -0,0 +1,127 @@
+package lit_go_sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SessionSig is the session signature a node accepts, a session key signing
+// the resources and abilities it was granted and until when
+type SessionSig struct {
+	// Sig is the hex encoded signature of SignedMessage
+	Sig string `json:"sig"`
+	// DerivedVia is how the signature was made, litSessionSignViaNacl
+	DerivedVia string `json:"derivedVia"`
+	// SignedMessage is the JSON encoded SessionMessage that was signed
+	SignedMessage string `json:"signedMessage"`
+	// Address is the hex encoded public key of the session key
+	Address string `json:"address"`
+	// Algo is the signature algorithm, ed25519
+	Algo string `json:"algo,omitempty"`
+}
+
+// SessionMessage is the message signed by a SessionSig
+type SessionMessage struct {
+	// SessionKey is the hex encoded public key of the session key
+	SessionKey string `json:"sessionKey"`
+	// ResourceAbilityRequests are the abilities granted over Lit resources
+	ResourceAbilityRequests []ResourceAbilityRequest `json:"resourceAbilityRequests"`
+	// Capabilities are the auth sigs delegating the abilities to the session key
+	Capabilities []json.RawMessage `json:"capabilities"`
+	// IssuedAt is when the session sig was made
+	IssuedAt time.Time `json:"issuedAt"`
+	// Expiration is when the nodes stop accepting the session sig
+	Expiration time.Time `json:"expiration"`
+	// NodeAddress is the URL of the node the session sig is for
+	NodeAddress string `json:"nodeAddress"`
+}
+
+// Message parses the signed message
+func (s SessionSig) Message() (*SessionMessage, error) {
+	var message SessionMessage
+	if err := json.Unmarshal([]byte(s.SignedMessage), &message); err != nil {
+		return nil, fmt.Errorf("failed to parse session sig signed message: %w", err)
+	}
+	return &message, nil
+}
+
+// SessionSigs maps the URL of each node to its session sig. It is what
+// GetSessionSigs returns under "sessionSigs", and marshals to the same JSON,
+// so it can be stored and passed back to ExecuteJs, PKPSign and DecryptString.
+type SessionSigs map[string]SessionSig
+
+// SessionSigsFromResult extracts the session sigs from a GetSessionSigs result
+func SessionSigsFromResult(result map[string]interface{}) (SessionSigs, error) {
+	raw, ok := result["sessionSigs"]
+	if !ok || raw == nil {
+		return nil, errors.New("no sessionSigs in result")
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode session sigs: %w", err)
+	}
+	var sessionSigs SessionSigs
+	if err := json.Unmarshal(data, &sessionSigs); err != nil {
+		return nil, fmt.Errorf("failed to decode session sigs: %w", err)
+	}
+	return sessionSigs, nil
+}
+
+// messages parses the signed messages, in the order of the node URLs
+func (s SessionSigs) messages() ([]*SessionMessage, error) {
+	if len(s) == 0 {
+		return nil, errors.New("no session sigs")
+	}
+	nodes := make([]string, 0, len(s))
+	for node := range s {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	messages := make([]*SessionMessage, 0, len(nodes))
+	for _, node := range nodes {
+		message, err := s[node].Message()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", node, err)
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// ExpiresAt returns when the first of the session sigs expires
+func (s SessionSigs) ExpiresAt() (time.Time, error) {
+	messages, err := s.messages()
+	if err != nil {
+		return time.Time{}, err
+	}
+	expiresAt := messages[0].Expiration
+	for _, message := range messages[1:] {
+		if message.Expiration.Before(expiresAt) {
+			expiresAt = message.Expiration
+		}
+	}
+	return expiresAt, nil
+}
+
+// Expired reports whether any of the session sigs has expired at t
+func (s SessionSigs) Expired(t time.Time) (bool, error) {
+	expiresAt, err := s.ExpiresAt()
+	if err != nil {
+		return false, err
+	}
+	return !t.Before(expiresAt), nil
+}
+
+// ResourceAbilityRequests returns the abilities the session sigs grant. Every
+// node is granted the same ones.
+func (s SessionSigs) ResourceAbilityRequests() ([]ResourceAbilityRequest, error) {
+	messages, err := s.messages()
+	if err != nil {
+		return nil, err
+	}
+	return messages[0].ResourceAbilityRequests, nil
+}
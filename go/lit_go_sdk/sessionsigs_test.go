@@ -0,0 +1,115 @@
+package lit_go_sdk
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// sessionSigsResult is a GetSessionSigs result in the shape the JS SDK returns
+const sessionSigsResult = `{
+	"success": true,
+	"sessionSigs": {
+		"https://node-1:443": {
+			"sig": "aa",
+			"derivedVia": "litSessionSignViaNacl",
+			"signedMessage": "{\"sessionKey\":\"0a1b\",\"resourceAbilityRequests\":[{\"resource\":{\"resource\":\"*\",\"resourcePrefix\":\"lit-litaction\"},\"ability\":\"lit-action-execution\"}],\"capabilities\":[{\"sig\":\"0x01\"}],\"issuedAt\":\"2024-05-01T10:00:00.000Z\",\"expiration\":\"2024-05-01T10:10:00.000Z\",\"nodeAddress\":\"https://node-1:443\"}",
+			"address": "0a1b",
+			"algo": "ed25519"
+		},
+		"https://node-2:443": {
+			"sig": "bb",
+			"derivedVia": "litSessionSignViaNacl",
+			"signedMessage": "{\"sessionKey\":\"0a1b\",\"resourceAbilityRequests\":[{\"resource\":{\"resource\":\"*\",\"resourcePrefix\":\"lit-litaction\"},\"ability\":\"lit-action-execution\"}],\"capabilities\":[{\"sig\":\"0x01\"}],\"issuedAt\":\"2024-05-01T10:00:00.000Z\",\"expiration\":\"2024-05-01T10:09:59.000Z\",\"nodeAddress\":\"https://node-2:443\"}",
+			"address": "0a1b",
+			"algo": "ed25519"
+		}
+	}
+}`
+
+func parseSessionSigsResult(t *testing.T) SessionSigs {
+	t.Helper()
+	var result map[string]interface{}
+	if err := json.Unmarshal([]byte(sessionSigsResult), &result); err != nil {
+		t.Fatal(err)
+	}
+	sessionSigs, err := SessionSigsFromResult(result)
+	if err != nil {
+		t.Fatalf("SessionSigsFromResult() error = %v", err)
+	}
+	return sessionSigs
+}
+
+func TestSessionSigs_Inspect(t *testing.T) {
+	sessionSigs := parseSessionSigsResult(t)
+	if len(sessionSigs) != 2 || sessionSigs["https://node-1:443"].Sig != "aa" {
+		t.Fatalf("SessionSigsFromResult() = %+v", sessionSigs)
+	}
+
+	expiresAt, err := sessionSigs.ExpiresAt()
+	if err != nil {
+		t.Fatalf("ExpiresAt() error = %v", err)
+	}
+	if want := time.Date(2024, 5, 1, 10, 9, 59, 0, time.UTC); !expiresAt.Equal(want) {
+		t.Errorf("ExpiresAt() = %v, want the earliest expiration %v", expiresAt, want)
+	}
+	if expired, _ := sessionSigs.Expired(expiresAt.Add(-time.Second)); expired {
+		t.Error("Expected the session sigs not to have expired before ExpiresAt")
+	}
+	if expired, _ := sessionSigs.Expired(expiresAt); !expired {
+		t.Error("Expected the session sigs to have expired at ExpiresAt")
+	}
+
+	requests, err := sessionSigs.ResourceAbilityRequests()
+	if err != nil {
+		t.Fatalf("ResourceAbilityRequests() error = %v", err)
+	}
+	want := []ResourceAbilityRequest{{Resource: LitResource{Resource: "*", ResourcePrefix: "lit-litaction"}, Ability: "lit-action-execution"}}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("ResourceAbilityRequests() = %+v, want %+v", requests, want)
+	}
+
+	message, err := sessionSigs["https://node-2:443"].Message()
+	if err != nil {
+		t.Fatalf("Message() error = %v", err)
+	}
+	if message.SessionKey != "0a1b" || message.NodeAddress != "https://node-2:443" || len(message.Capabilities) != 1 {
+		t.Errorf("Message() = %+v", message)
+	}
+}
+
+func TestSessionSigs_JSONRoundTrip(t *testing.T) {
+	sessionSigs := parseSessionSigsResult(t)
+
+	data, err := json.Marshal(sessionSigs)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var decoded SessionSigs
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if !reflect.DeepEqual(decoded, sessionSigs) {
+		t.Errorf("Round trip = %+v, want %+v", decoded, sessionSigs)
+	}
+
+	// Signed messages keep their exact bytes, so the nodes can verify them
+	var original map[string]map[string]map[string]string
+	json.Unmarshal([]byte(sessionSigsResult), &original)
+	if got, want := decoded["https://node-1:443"].SignedMessage, original["sessionSigs"]["https://node-1:443"]["signedMessage"]; got != want {
+		t.Errorf("SignedMessage = %s, want %s", got, want)
+	}
+}
+
+func TestSessionSigs_Errors(t *testing.T) {
+	if _, err := SessionSigsFromResult(map[string]interface{}{"success": true}); err == nil {
+		t.Error("Expected an error for a result without session sigs")
+	}
+	if _, err := (SessionSigs{}).ExpiresAt(); err == nil {
+		t.Error("Expected an error for empty session sigs")
+	}
+	if _, err := (SessionSigs{"node": {SignedMessage: "not json"}}).ExpiresAt(); err == nil {
+		t.Error("Expected an error for an invalid signed message")
+	}
+}
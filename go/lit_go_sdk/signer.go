@@ -0,0 +1,270 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrNoSigner is returned when the server needs a signature and no Signer
+// has been set with SetSigner, SetAuthToken or NewLitContractsClient
+var ErrNoSigner = errors.New("lit: no signer set")
+
+// Signer holds the wallet the server authenticates with. Its key never leaves
+// the Go process: the server asks the client for signatures when it needs
+// them, e.g. for the auth sig behind session signatures.
+type Signer interface {
+	// Address returns the address of the wallet
+	Address() common.Address
+	// SignMessage signs message as an EIP-191 personal message, like
+	// personal_sign, returning the 65 byte [R || S || V] signature
+	SignMessage(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// TransactionSigner is a Signer that can also sign transactions, which the
+// Lit contracts client needs to mint PKPs
+type TransactionSigner interface {
+	Signer
+	// SignTransaction signs tx for the chain chainID
+	SignTransaction(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// PrivateKeySigner is a TransactionSigner holding its private key in memory
+type PrivateKeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+var _ TransactionSigner = (*PrivateKeySigner)(nil)
+
+// NewPrivateKeySigner returns a signer for key
+func NewPrivateKeySigner(key *ecdsa.PrivateKey) *PrivateKeySigner {
+	return &PrivateKeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+// PrivateKeySignerFromHex returns a signer for the hex encoded private key,
+// with or without 0x prefix
+func PrivateKeySignerFromHex(hexKey string) (*PrivateKeySigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid private key: %v", ErrInvalidParams, err)
+	}
+	return NewPrivateKeySigner(key), nil
+}
+
+// Address implements Signer
+func (s *PrivateKeySigner) Address() common.Address {
+	return s.address
+}
+
+// SignMessage implements Signer
+func (s *PrivateKeySigner) SignMessage(ctx context.Context, message []byte) ([]byte, error) {
+	sig, err := crypto.Sign(accounts.TextHash(message), s.key)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
+
+// SignTransaction implements TransactionSigner
+func (s *PrivateKeySigner) SignTransaction(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+// SetSigner makes the server use signer for the wallet it authenticates
+// with, in place of an auth token. Session signatures, auth sigs and Lit
+// contracts transactions are then signed by signer in this process.
+func (c *LitNodeClient) SetSigner(signer Signer) (map[string]interface{}, error) {
+	return c.SetSignerContext(context.Background(), signer)
+}
+
+// SetSignerContext is like SetSigner but uses ctx for the request to the server
+func (c *LitNodeClient) SetSignerContext(ctx context.Context, signer Signer) (map[string]interface{}, error) {
+	if signer == nil {
+		return nil, fmt.Errorf("%w: nil signer", ErrInvalidParams)
+	}
+	result, err := c.post(ctx, "/setSigner", map[string]string{"address": signer.Address().Hex()})
+	if err != nil {
+		return nil, err
+	}
+	c.setSigner(signer)
+	return result, nil
+}
+
+// setSigner replaces the signer answering the server's signature requests.
+// The cached session sigs belong to the previous wallet, so they are dropped
+// if the address changed.
+func (c *LitNodeClient) setSigner(signer Signer) {
+	c.signerMu.Lock()
+	previous := c.signer
+	c.signer = signer
+	c.signerMu.Unlock()
+
+	if c.sessions != nil && (previous == nil || previous.Address() != signer.Address()) {
+		c.sessions.Invalidate()
+	}
+}
+
+// currentSigner returns the signer answering the server's signature requests
+func (c *LitNodeClient) currentSigner() Signer {
+	c.signerMu.Lock()
+	defer c.signerMu.Unlock()
+	return c.signer
+}
+
+// signatureRequest is sent by the server in place of a response when the
+// operation it is running needs a signature from the client's Signer. The
+// client answers it on /signer/respond, whose response is the next step of
+// the operation: its result or another signature request.
+type signatureRequest struct {
+	ID string `json:"id"`
+	// Message is the message to sign with SignMessage
+	Message *hexutil.Bytes `json:"message,omitempty"`
+	// Transaction is the transaction to sign with SignTransaction
+	Transaction *unsignedTransaction `json:"transaction,omitempty"`
+}
+
+// signatureResponse answers a signatureRequest
+type signatureResponse struct {
+	ID                string        `json:"id"`
+	MessageSignature  hexutil.Bytes `json:"messageSignature,omitempty"`
+	SignedTransaction hexutil.Bytes `json:"signedTransaction,omitempty"`
+	Error             string        `json:"error,omitempty"`
+}
+
+// unsignedTransaction is a transaction populated by ethers, to be signed
+type unsignedTransaction struct {
+	Type                 hexutil.Uint64  `json:"type"`
+	ChainID              *hexutil.Big    `json:"chainId"`
+	Nonce                hexutil.Uint64  `json:"nonce"`
+	To                   *common.Address `json:"to"`
+	Value                *hexutil.Big    `json:"value"`
+	Data                 hexutil.Bytes   `json:"data"`
+	GasLimit             hexutil.Uint64  `json:"gasLimit"`
+	GasPrice             *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+}
+
+// toTransaction returns the transaction as a legacy or EIP-1559 transaction
+func (t unsignedTransaction) toTransaction() (*types.Transaction, error) {
+	if t.ChainID == nil {
+		return nil, fmt.Errorf("transaction without a chain ID")
+	}
+	switch t.Type {
+	case types.LegacyTxType:
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(t.Nonce),
+			GasPrice: t.GasPrice.ToInt(),
+			Gas:      uint64(t.GasLimit),
+			To:       t.To,
+			Value:    t.Value.ToInt(),
+			Data:     t.Data,
+		}), nil
+	case types.DynamicFeeTxType:
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:   t.ChainID.ToInt(),
+			Nonce:     uint64(t.Nonce),
+			GasTipCap: t.MaxPriorityFeePerGas.ToInt(),
+			GasFeeCap: t.MaxFeePerGas.ToInt(),
+			Gas:       uint64(t.GasLimit),
+			To:        t.To,
+			Value:     t.Value.ToInt(),
+			Data:      t.Data,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d", t.Type)
+	}
+}
+
+// answerSignatureRequests signs what the server asks for in result until it
+// sends the result of the operation
+func (c *LitNodeClient) answerSignatureRequests(ctx context.Context, endpoint string, result map[string]interface{}) (map[string]interface{}, error) {
+	for result["signatureRequest"] != nil {
+		var envelope struct {
+			SignatureRequest signatureRequest `json:"signatureRequest"`
+		}
+		if err := decodeResult(result, &envelope); err != nil {
+			return nil, fmt.Errorf("failed to decode signature request from %s: %w", endpoint, err)
+		}
+		request := envelope.SignatureRequest
+
+		response, signErr := c.sign(ctx, request)
+		if signErr != nil {
+			// Let the operation fail on the server rather than wait for a timeout
+			response = signatureResponse{ID: request.ID, Error: signErr.Error()}
+		}
+		var err error
+		result, err = c.sendRequest(ctx, "/signer/respond", response)
+		if signErr != nil {
+			return nil, signErr
+		}
+		if err != nil {
+			// The answer is part of the operation on endpoint, so report its
+			// errors there rather than on /signer/respond
+			var litErr *LitError
+			if errors.As(err, &litErr) {
+				litErr.Endpoint = endpoint
+			}
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// sign answers a signature request with the client's signer
+func (c *LitNodeClient) sign(ctx context.Context, request signatureRequest) (signatureResponse, error) {
+	signer := c.currentSigner()
+	if signer == nil {
+		return signatureResponse{}, ErrNoSigner
+	}
+	response := signatureResponse{ID: request.ID}
+
+	switch {
+	case request.Message != nil:
+		sig, err := signer.SignMessage(ctx, *request.Message)
+		if err != nil {
+			return signatureResponse{}, fmt.Errorf("lit: signer failed to sign message: %w", err)
+		}
+		if len(sig) != crypto.SignatureLength {
+			return signatureResponse{}, fmt.Errorf("lit: signer returned a %d byte signature, want %d", len(sig), crypto.SignatureLength)
+		}
+		// ethers expects V to be 27 or 28, some signers return 0 or 1
+		if sig[crypto.RecoveryIDOffset] < 27 {
+			sig = append([]byte(nil), sig...)
+			sig[crypto.RecoveryIDOffset] += 27
+		}
+		response.MessageSignature = sig
+
+	case request.Transaction != nil:
+		txSigner, ok := signer.(TransactionSigner)
+		if !ok {
+			return signatureResponse{}, fmt.Errorf("lit: signer cannot sign transactions, it must implement TransactionSigner")
+		}
+		tx, err := request.Transaction.toTransaction()
+		if err != nil {
+			return signatureResponse{}, fmt.Errorf("lit: %w", err)
+		}
+		signed, err := txSigner.SignTransaction(ctx, tx, request.Transaction.ChainID.ToInt())
+		if err != nil {
+			return signatureResponse{}, fmt.Errorf("lit: signer failed to sign transaction: %w", err)
+		}
+		if response.SignedTransaction, err = signed.MarshalBinary(); err != nil {
+			return signatureResponse{}, err
+		}
+
+	default:
+		return signatureResponse{}, fmt.Errorf("lit: empty signature request %s", request.ID)
+	}
+	return response, nil
+}
@@ -0,0 +1,175 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// signerTestKey is a well-known development key, never holding real funds
+const signerTestKey = "0xac0974bec39a17e36ba4a6b4d238ff944bacb478cbed5efcae784d7bf4f2ff80"
+
+func TestPrivateKeySigner(t *testing.T) {
+	signer, err := PrivateKeySignerFromHex(signerTestKey)
+	if err != nil {
+		t.Fatalf("PrivateKeySignerFromHex() error = %v", err)
+	}
+	if want := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"); signer.Address() != want {
+		t.Errorf("Address() = %s, want %s", signer.Address(), want)
+	}
+
+	message := []byte("hello")
+	sig, err := signer.SignMessage(context.Background(), message)
+	if err != nil {
+		t.Fatalf("SignMessage() error = %v", err)
+	}
+	if v := sig[crypto.RecoveryIDOffset]; v != 27 && v != 28 {
+		t.Errorf("V = %d, want 27 or 28", v)
+	}
+	sig[crypto.RecoveryIDOffset] -= 27
+	pub, err := crypto.SigToPub(accounts.TextHash(message), sig)
+	if err != nil || crypto.PubkeyToAddress(*pub) != signer.Address() {
+		t.Errorf("Expected the signature to recover to the signer, got %v, %v", pub, err)
+	}
+
+	chainID := big.NewInt(175188)
+	to := common.HexToAddress("0x01")
+	tx, err := signer.SignTransaction(context.Background(), types.NewTx(&types.DynamicFeeTx{ChainID: chainID, To: &to}), chainID)
+	if err != nil {
+		t.Fatalf("SignTransaction() error = %v", err)
+	}
+	if sender, err := types.Sender(types.LatestSignerForChainID(chainID), tx); err != nil || sender != signer.Address() {
+		t.Errorf("Sender() = %s, %v, want the signer", sender, err)
+	}
+
+	if _, err := PrivateKeySignerFromHex("0xkey"); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams for an invalid key, got %v", err)
+	}
+}
+
+// recoveryIDSigner signs with V as 0 or 1, like some remote signers do
+type recoveryIDSigner struct {
+	*PrivateKeySigner
+}
+
+func (s recoveryIDSigner) SignMessage(ctx context.Context, message []byte) ([]byte, error) {
+	return crypto.Sign(accounts.TextHash(message), s.key)
+}
+
+// signatureRequestHandler asks for a signature of "hello" on
+// /litContractsClient/mintWithAuth and passes the answer on /signer/respond
+// to respond
+func signatureRequestHandler(t *testing.T, respond func(response signatureResponse) string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/setSigner":
+			w.Write([]byte(`{"success": true}`))
+		case "/litContractsClient/mintWithAuth":
+			w.Write([]byte(`{"signatureRequest": {"id": "req-1", "message": "` + hexutil.Encode([]byte("hello")) + `"}}`))
+		case "/signer/respond":
+			var response signatureResponse
+			if err := json.Unmarshal(body, &response); err != nil {
+				t.Errorf("Invalid signature response %s: %v", body, err)
+			}
+			w.Write([]byte(respond(response)))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	})
+}
+
+func TestSignatureRequest_Answered(t *testing.T) {
+	key, _ := PrivateKeySignerFromHex(signerTestKey)
+	client := newTestClient(t, signatureRequestHandler(t, func(response signatureResponse) string {
+		if response.ID != "req-1" || response.Error != "" {
+			t.Errorf("Unexpected signature response %+v", response)
+		}
+		if v := response.MessageSignature[crypto.RecoveryIDOffset]; v != 27 && v != 28 {
+			t.Errorf("Expected V to be normalized to 27 or 28, got %d", v)
+		}
+		return `{"pkp": {"tokenId": "0x1"}}`
+	}))
+
+	if _, err := client.SetSigner(recoveryIDSigner{key}); err != nil {
+		t.Fatalf("SetSigner() error = %v", err)
+	}
+	result, err := client.MintWithAuth(MintWithAuthParams{AuthMethod: LitActionAuthMethod("token")})
+	if err != nil {
+		t.Fatalf("MintWithAuth() error = %v", err)
+	}
+	if _, ok := result["pkp"]; !ok {
+		t.Errorf("Expected the result of the operation after the signature, got %v", result)
+	}
+}
+
+func TestSignatureRequest_NoSigner(t *testing.T) {
+	responded := false
+	client := newTestClient(t, signatureRequestHandler(t, func(response signatureResponse) string {
+		responded = true
+		if !strings.Contains(response.Error, "no signer") {
+			t.Errorf("Expected the server to be told there is no signer, got %+v", response)
+		}
+		return `{"error": {"message": "SDK signer failed"}}`
+	}))
+
+	if _, err := client.MintWithAuth(MintWithAuthParams{AuthMethod: LitActionAuthMethod("token")}); !errors.Is(err, ErrNoSigner) {
+		t.Fatalf("MintWithAuth() error = %v, want ErrNoSigner", err)
+	}
+	if !responded {
+		t.Error("Expected the signature request to be answered with an error")
+	}
+}
+
+func TestSignatureRequest_ErrorReportsEndpoint(t *testing.T) {
+	key, _ := PrivateKeySignerFromHex(signerTestKey)
+	client := newTestClient(t, signatureRequestHandler(t, func(response signatureResponse) string {
+		return `{"success": false, "error": "Invalid signature"}`
+	}))
+	if _, err := client.SetSigner(key); err != nil {
+		t.Fatalf("SetSigner() error = %v", err)
+	}
+
+	_, err := client.MintWithAuth(MintWithAuthParams{AuthMethod: LitActionAuthMethod("token")})
+	var litErr *LitError
+	if !errors.As(err, &litErr) {
+		t.Fatalf("MintWithAuth() error = %v, want a *LitError", err)
+	}
+	if litErr.Endpoint != "/litContractsClient/mintWithAuth" {
+		t.Errorf("Endpoint = %s, want /litContractsClient/mintWithAuth", litErr.Endpoint)
+	}
+}
+
+func TestNewLitContractsClient_KeyNotSent(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), strings.TrimPrefix(signerTestKey, "0x")) {
+			t.Errorf("Expected the private key not to be sent, got %s", body)
+		}
+		if !strings.Contains(string(body), `"address":"0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"`) {
+			t.Errorf("Expected the signer address to be sent, got %s", body)
+		}
+		w.Write([]byte(`{"success": true}`))
+	}))
+
+	if _, err := client.NewLitContractsClient(LitContractsClientConfig{}); !errors.Is(err, ErrNoSigner) {
+		t.Errorf("NewLitContractsClient() without a signer error = %v, want ErrNoSigner", err)
+	}
+	if _, err := client.NewLitContractsClient(LitContractsClientConfig{PrivateKey: signerTestKey, Network: LitNetworkDatilDev}); err != nil {
+		t.Fatalf("NewLitContractsClient() error = %v", err)
+	}
+	if _, err := client.SetAuthToken(signerTestKey); err != nil {
+		t.Fatalf("SetAuthToken() error = %v", err)
+	}
+}
@@ -0,0 +1,355 @@
+package lit_go_sdk
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultSiweStatement is the statement of the SIWE messages made by
+// createSiweMessage in @lit-protocol/auth-helpers when none is given
+const DefaultSiweStatement = "This is a test statement.  You can put anything you want here."
+
+// siweHeaderSuffix ends the first line of a SIWE message, after the domain
+const siweHeaderSuffix = " wants you to sign in with your Ethereum account:"
+
+// siweTimeFormat is the format of JS Date.toISOString, used for the times
+// createSiweMessage sets
+const siweTimeFormat = "2006-01-02T15:04:05.000Z"
+
+// siweNoncePattern is what the siwe package checks nonces against
+var siweNoncePattern = regexp.MustCompile(`^[a-zA-Z0-9]{8,}$`)
+
+// SiweMessage is an EIP-4361 Sign-In with Ethereum message
+type SiweMessage struct {
+	// Domain is the domain asking for the signature, e.g. localhost
+	Domain string
+	// Address is the EIP-55 checksummed address of the signer
+	Address string
+	// Statement is the human readable text shown to the signer, followed by
+	// the description of the ReCap capabilities if there are any
+	Statement string
+	// URI is the subject of the signature, e.g. lit:session:<session key>
+	URI string
+	// Version is the version of the message format, 1
+	Version string
+	// ChainID is the EIP-155 ID of the chain the address is on
+	ChainID int64
+	// Nonce protects against replays, the latest block hash for Lit nodes
+	Nonce string
+	// IssuedAt is when the message was made, in RFC 3339 format
+	IssuedAt string
+	// ExpirationTime is when the signature stops being valid, if set
+	ExpirationTime string
+	// NotBefore is when the signature starts being valid, if set
+	NotBefore string
+	// RequestID identifies the request, if set
+	RequestID string
+	// Resources are URIs the signature applies to, the last one holding the
+	// ReCap capabilities if there are any
+	Resources []string
+}
+
+// SiweMessageParams are the parameters of NewSiweMessage, the same as those
+// of createSiweMessage in @lit-protocol/auth-helpers
+type SiweMessageParams struct {
+	// URI is the subject of the signature, https://localhost/login by default
+	URI string
+	// Expiration is when the signature stops being valid in RFC 3339
+	// format, one week from now by default
+	Expiration string
+	// Resources are the abilities delegated to URI, as a ReCap
+	Resources []ResourceAbilityRequest
+	// WalletAddress is the EIP-55 checksummed address of the signer
+	WalletAddress string
+	// Nonce protects against replays, random by default. Lit nodes require
+	// the latest block hash, the "latestBlockhash" property of GetProperty.
+	Nonce string
+	// Domain is the domain asking for the signature, localhost by default
+	Domain string
+	// Statement is the human readable text shown to the signer,
+	// DefaultSiweStatement by default
+	Statement string
+	// ChainID is the EIP-155 ID of the chain, 1 by default
+	ChainID int64
+	// IssuedAt is when the message was made in RFC 3339 format, now by default
+	IssuedAt string
+}
+
+// NewSiweMessage makes the SIWE message createSiweMessage in
+// @lit-protocol/auth-helpers makes for params, without a round trip to the
+// server. Its String method returns the text to sign.
+func NewSiweMessage(params SiweMessageParams) (*SiweMessage, error) {
+	now := time.Now().UTC()
+	m := &SiweMessage{
+		Domain:         valueOr(params.Domain, "localhost"),
+		Address:        params.WalletAddress,
+		Statement:      valueOr(params.Statement, DefaultSiweStatement),
+		URI:            valueOr(params.URI, "https://localhost/login"),
+		Version:        "1",
+		ChainID:        params.ChainID,
+		Nonce:          params.Nonce,
+		IssuedAt:       valueOr(params.IssuedAt, now.Format(siweTimeFormat)),
+		ExpirationTime: valueOr(params.Expiration, now.Add(7*24*time.Hour).Format(siweTimeFormat)),
+	}
+	if m.ChainID == 0 {
+		m.ChainID = 1
+	}
+	if m.Nonce == "" {
+		nonce, err := randomSiweNonce()
+		if err != nil {
+			return nil, err
+		}
+		m.Nonce = nonce
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Each request is added as its own ReCap and merged into the previous
+	// ones, like addRecapToSiweMessage does in a loop over the requests
+	for _, request := range params.Resources {
+		recap := NewRecap()
+		if err := recap.AddResourceAbilityRequest(request); err != nil {
+			return nil, err
+		}
+		if err := m.AddRecap(recap); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Validate checks the fields the siwe package checks. The error wraps
+// ErrInvalidParams.
+func (m *SiweMessage) Validate() error {
+	if m.Domain == "" {
+		return fmt.Errorf("%w: SIWE message without a domain", ErrInvalidParams)
+	}
+	// A line break would let a field add lines of its own to the message
+	fields := []struct{ name, value string }{
+		{"domain", m.Domain}, {"URI", m.URI}, {"statement", m.Statement}, {"request ID", m.RequestID},
+	}
+	for _, resource := range m.Resources {
+		fields = append(fields, struct{ name, value string }{"resource", resource})
+	}
+	for _, field := range fields {
+		if strings.ContainsAny(field.value, "\r\n") {
+			return fmt.Errorf("%w: SIWE %s contains a line break", ErrInvalidParams, field.name)
+		}
+	}
+	if !common.IsHexAddress(m.Address) || common.HexToAddress(m.Address).Hex() != m.Address {
+		return fmt.Errorf("%w: SIWE address %q is not an EIP-55 checksummed address", ErrInvalidParams, m.Address)
+	}
+	if u, err := url.Parse(m.URI); err != nil || u.Scheme == "" {
+		return fmt.Errorf("%w: invalid SIWE URI %q", ErrInvalidParams, m.URI)
+	}
+	if m.Version != "1" {
+		return fmt.Errorf("%w: unsupported SIWE version %q", ErrInvalidParams, m.Version)
+	}
+	if !siweNoncePattern.MatchString(m.Nonce) {
+		return fmt.Errorf("%w: SIWE nonce %q needs at least 8 alphanumeric characters", ErrInvalidParams, m.Nonce)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, m.IssuedAt); err != nil {
+		return fmt.Errorf("%w: invalid SIWE issued at time %q", ErrInvalidParams, m.IssuedAt)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, m.ExpirationTime); m.ExpirationTime != "" && err != nil {
+		return fmt.Errorf("%w: invalid SIWE expiration time %q", ErrInvalidParams, m.ExpirationTime)
+	}
+	if _, err := time.Parse(time.RFC3339Nano, m.NotBefore); m.NotBefore != "" && err != nil {
+		return fmt.Errorf("%w: invalid SIWE not before time %q", ErrInvalidParams, m.NotBefore)
+	}
+	return nil
+}
+
+// String returns the text of the message, which is what gets signed
+func (m *SiweMessage) String() string {
+	var b strings.Builder
+	b.WriteString(m.Domain + siweHeaderSuffix + "\n")
+	b.WriteString(m.Address + "\n\n")
+	if m.Statement != "" {
+		b.WriteString(m.Statement + "\n")
+	}
+	b.WriteString("\nURI: " + m.URI)
+	b.WriteString("\nVersion: " + m.Version)
+	b.WriteString("\nChain ID: " + strconv.FormatInt(m.ChainID, 10))
+	b.WriteString("\nNonce: " + m.Nonce)
+	b.WriteString("\nIssued At: " + m.IssuedAt)
+	if m.ExpirationTime != "" {
+		b.WriteString("\nExpiration Time: " + m.ExpirationTime)
+	}
+	if m.NotBefore != "" {
+		b.WriteString("\nNot Before: " + m.NotBefore)
+	}
+	if m.RequestID != "" {
+		b.WriteString("\nRequest ID: " + m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		b.WriteString("\nResources:")
+		for _, resource := range m.Resources {
+			b.WriteString("\n- " + resource)
+		}
+	}
+	return b.String()
+}
+
+// ParseSiweMessage parses the text of a SIWE message
+func ParseSiweMessage(message string) (*SiweMessage, error) {
+	lines := strings.Split(message, "\n")
+	invalid := func(format string, args ...interface{}) (*SiweMessage, error) {
+		return nil, fmt.Errorf("%w: invalid SIWE message: "+format, append([]interface{}{ErrInvalidParams}, args...)...)
+	}
+	if len(lines) < 4 {
+		return invalid("too short")
+	}
+
+	var m SiweMessage
+	var ok bool
+	if m.Domain, ok = strings.CutSuffix(lines[0], siweHeaderSuffix); !ok {
+		return invalid("unexpected header %q", lines[0])
+	}
+	m.Address = lines[1]
+	if lines[2] != "" {
+		return invalid("expected an empty line after the address")
+	}
+	rest := lines[3:]
+	if rest[0] != "" {
+		m.Statement = rest[0]
+		rest = rest[1:]
+	}
+	if len(rest) == 0 || rest[0] != "" {
+		return invalid("expected an empty line after the statement")
+	}
+	rest = rest[1:]
+
+	// field reads the line starting with prefix if it is next
+	field := func(prefix string, required bool) (string, error) {
+		if len(rest) > 0 && strings.HasPrefix(rest[0], prefix) {
+			value := strings.TrimPrefix(rest[0], prefix)
+			rest = rest[1:]
+			return value, nil
+		}
+		if required {
+			return "", fmt.Errorf("%w: invalid SIWE message: missing %q", ErrInvalidParams, strings.TrimSuffix(prefix, ": "))
+		}
+		return "", nil
+	}
+	var chainID string
+	var err error
+	for _, f := range []struct {
+		prefix   string
+		required bool
+		value    *string
+	}{
+		{"URI: ", true, &m.URI},
+		{"Version: ", true, &m.Version},
+		{"Chain ID: ", true, &chainID},
+		{"Nonce: ", true, &m.Nonce},
+		{"Issued At: ", true, &m.IssuedAt},
+		{"Expiration Time: ", false, &m.ExpirationTime},
+		{"Not Before: ", false, &m.NotBefore},
+		{"Request ID: ", false, &m.RequestID},
+	} {
+		if *f.value, err = field(f.prefix, f.required); err != nil {
+			return nil, err
+		}
+	}
+	if m.ChainID, err = strconv.ParseInt(chainID, 10, 64); err != nil {
+		return invalid("chain ID %q", chainID)
+	}
+	if len(rest) > 0 && rest[0] == "Resources:" {
+		for _, line := range rest[1:] {
+			resource, ok := strings.CutPrefix(line, "- ")
+			if !ok {
+				return invalid("unexpected resource line %q", line)
+			}
+			m.Resources = append(m.Resources, resource)
+		}
+		rest = nil
+	}
+	if len(rest) > 0 {
+		return invalid("unexpected line %q", rest[0])
+	}
+	return &m, nil
+}
+
+// Recap returns the ReCap in the last resource of the message, or nil if
+// there is none
+func (m *SiweMessage) Recap() (*Recap, error) {
+	if len(m.Resources) == 0 || !strings.HasPrefix(m.Resources[len(m.Resources)-1], recapURNPrefix) {
+		return nil, nil
+	}
+	recap, err := DecodeRecap(m.Resources[len(m.Resources)-1])
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(m.Statement, recap.Statement()) {
+		return nil, fmt.Errorf("%w: SIWE statement does not describe its ReCap", ErrInvalidParams)
+	}
+	return recap, nil
+}
+
+// AddRecap delegates the capabilities of recap, merging them into the ReCap
+// of the message if it has one, like add_to_siwe_message in siwe-recap
+func (m *SiweMessage) AddRecap(recap *Recap) error {
+	merged := recap.clone()
+	// A ReCap that cannot be read cannot be merged, and a second one would
+	// make a message the siwe package rejects
+	existing, err := m.Recap()
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		previous := existing.Statement()
+		merged.merge(existing)
+		encoded, err := merged.Encode()
+		if err != nil {
+			return err
+		}
+		m.Statement = m.Statement[:len(m.Statement)-len(previous)] + merged.Statement()
+		m.Resources[len(m.Resources)-1] = encoded
+		return nil
+	}
+
+	encoded, err := merged.Encode()
+	if err != nil {
+		return err
+	}
+	if m.Statement == "" {
+		m.Statement = merged.Statement()
+	} else {
+		m.Statement += " " + merged.Statement()
+	}
+	m.Resources = append(m.Resources, encoded)
+	return nil
+}
+
+// randomSiweNonce returns a random alphanumeric nonce of 96 bits, like
+// generateNonce in the siwe package
+func randomSiweNonce() (string, error) {
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	nonce := make([]byte, 17)
+	for i := range nonce {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", err
+		}
+		nonce[i] = alphabet[n.Int64()]
+	}
+	return string(nonce), nil
+}
+
+// valueOr returns value, or fallback if it is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,297 @@
+package lit_go_sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	siweTestAddress = "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266"
+	siweTestNonce   = "0x9c22ff5f21f0b81b113e63f7db6da94fedef11b2119b4088b89664fb9a3cb658"
+)
+
+func TestNewSiweMessage(t *testing.T) {
+	tests := []struct {
+		name   string
+		params SiweMessageParams
+		want   string
+	}{
+		{
+			name: "session",
+			params: SiweMessageParams{
+				URI:           "lit:session:abc",
+				Expiration:    "2030-01-01T00:00:00.000Z",
+				Resources:     []ResourceAbilityRequest{LitActionResource("*").Request(LitAbilityLitActionExecution)},
+				WalletAddress: siweTestAddress,
+				Nonce:         siweTestNonce,
+				IssuedAt:      "2024-01-01T00:00:00.000Z",
+			},
+			want: `localhost wants you to sign in with your Ethereum account:
+0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266
+
+This is a test statement.  You can put anything you want here. I further authorize the stated URI to perform the following actions on my behalf: (1) '*': '*' for 'lit-litaction://*'. (2) 'Threshold': 'Execution' for 'lit-litaction://*'.
+
+URI: lit:session:abc
+Version: 1
+Chain ID: 1
+Nonce: 0x9c22ff5f21f0b81b113e63f7db6da94fedef11b2119b4088b89664fb9a3cb658
+Issued At: 2024-01-01T00:00:00.000Z
+Expiration Time: 2030-01-01T00:00:00.000Z
+Resources:
+- urn:recap:eyJhdHQiOnsibGl0LWxpdGFjdGlvbjovLyoiOnsiKi8qIjpbe31dLCJUaHJlc2hvbGQvRXhlY3V0aW9uIjpbe31dfX0sInByZiI6W119`,
+		},
+		{
+			name: "several resources",
+			params: SiweMessageParams{
+				URI:        "lit:session:abc",
+				Expiration: "2030-01-01T00:00:00.000Z",
+				Resources: []ResourceAbilityRequest{
+					LitPKPResource("*").Request(LitAbilityPKPSigning),
+					LitActionResource("*").Request(LitAbilityLitActionExecution),
+					LitAccessControlConditionResource("*").Request(LitAbilityAccessControlConditionDecryption),
+					LitAccessControlConditionResource("*").Request(LitAbilityAccessControlConditionSigning),
+				},
+				WalletAddress: siweTestAddress,
+				Nonce:         siweTestNonce,
+				IssuedAt:      "2024-01-01T00:00:00.000Z",
+			},
+			want: `localhost wants you to sign in with your Ethereum account:
+0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266
+
+This is a test statement.  You can put anything you want here. I further authorize the stated URI to perform the following actions on my behalf: (1) '*': '*' for 'lit-accesscontrolcondition://*'. (2) 'Threshold': 'Decryption', 'Signing' for 'lit-accesscontrolcondition://*'. (3) '*': '*' for 'lit-litaction://*'. (4) 'Threshold': 'Execution' for 'lit-litaction://*'. (5) '*': '*' for 'lit-pkp://*'. (6) 'Threshold': 'Signing' for 'lit-pkp://*'.
+
+URI: lit:session:abc
+Version: 1
+Chain ID: 1
+Nonce: 0x9c22ff5f21f0b81b113e63f7db6da94fedef11b2119b4088b89664fb9a3cb658
+Issued At: 2024-01-01T00:00:00.000Z
+Expiration Time: 2030-01-01T00:00:00.000Z
+Resources:
+- urn:recap:eyJhdHQiOnsibGl0LWFjY2Vzc2NvbnRyb2xjb25kaXRpb246Ly8qIjp7IiovKiI6W3t9XSwiVGhyZXNob2xkL0RlY3J5cHRpb24iOlt7fV0sIlRocmVzaG9sZC9TaWduaW5nIjpbe31dfSwibGl0LWxpdGFjdGlvbjovLyoiOnsiKi8qIjpbe31dLCJUaHJlc2hvbGQvRXhlY3V0aW9uIjpbe31dfSwibGl0LXBrcDovLyoiOnsiKi8qIjpbe31dLCJUaHJlc2hvbGQvU2lnbmluZyI6W3t9XX19LCJwcmYiOltdfQ`,
+		},
+		{
+			name: "custom fields without resources",
+			params: SiweMessageParams{
+				URI:           "https://example.com/login",
+				Expiration:    "2030-06-15T12:30:00.000Z",
+				WalletAddress: siweTestAddress,
+				Nonce:         "abcdefgh12345678",
+				Domain:        "example.com",
+				Statement:     "Sign in to Example.",
+				ChainID:       175188,
+				IssuedAt:      "2024-01-01T00:00:00.000Z",
+			},
+			want: `example.com wants you to sign in with your Ethereum account:
+0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266
+
+Sign in to Example.
+
+URI: https://example.com/login
+Version: 1
+Chain ID: 175188
+Nonce: abcdefgh12345678
+Issued At: 2024-01-01T00:00:00.000Z
+Expiration Time: 2030-06-15T12:30:00.000Z`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := NewSiweMessage(tt.params)
+			if err != nil {
+				t.Fatalf("NewSiweMessage() error = %v", err)
+			}
+			if got := message.String(); got != tt.want {
+				t.Errorf("String() = %s\nwant %s", got, tt.want)
+			}
+
+			parsed, err := ParseSiweMessage(tt.want)
+			if err != nil {
+				t.Fatalf("ParseSiweMessage() error = %v", err)
+			}
+			if !reflect.DeepEqual(parsed, message) {
+				t.Errorf("ParseSiweMessage() = %+v, want %+v", parsed, message)
+			}
+			recap, err := parsed.Recap()
+			if err != nil {
+				t.Fatalf("Recap() error = %v", err)
+			}
+			var requests []ResourceAbilityRequest
+			if recap != nil {
+				requests = recap.ResourceAbilityRequests()
+			}
+			if len(requests) != len(tt.params.Resources) {
+				t.Errorf("ResourceAbilityRequests() = %v, want %v", requests, tt.params.Resources)
+			}
+			for _, request := range tt.params.Resources {
+				if !containsRequest(requests, request) {
+					t.Errorf("Expected %v in the ReCap, got %v", request, requests)
+				}
+			}
+		})
+	}
+}
+
+// containsRequest reports whether requests contains request
+func containsRequest(requests []ResourceAbilityRequest, request ResourceAbilityRequest) bool {
+	for _, r := range requests {
+		if r == request {
+			return true
+		}
+	}
+	return false
+}
+
+func TestNewSiweMessage_Defaults(t *testing.T) {
+	message, err := NewSiweMessage(SiweMessageParams{WalletAddress: siweTestAddress})
+	if err != nil {
+		t.Fatalf("NewSiweMessage() error = %v", err)
+	}
+	if message.Domain != "localhost" || message.URI != "https://localhost/login" || message.ChainID != 1 || message.Statement != DefaultSiweStatement {
+		t.Errorf("Expected the createSiweMessage defaults, got %+v", message)
+	}
+	if len(message.Nonce) != 17 || message.IssuedAt == "" || message.ExpirationTime <= message.IssuedAt {
+		t.Errorf("Expected a random nonce and an expiration after the issue time, got %+v", message)
+	}
+	if len(message.Resources) != 0 || strings.Contains(message.String(), "Resources:") {
+		t.Errorf("Expected no resources, got %q", message.String())
+	}
+}
+
+func TestNewSiweMessage_Invalid(t *testing.T) {
+	valid := SiweMessageParams{WalletAddress: siweTestAddress, Nonce: siweTestNonce}
+	tests := []struct {
+		name   string
+		modify func(p *SiweMessageParams)
+	}{
+		{"no address", func(p *SiweMessageParams) { p.WalletAddress = "" }},
+		{"lowercase address", func(p *SiweMessageParams) { p.WalletAddress = strings.ToLower(siweTestAddress) }},
+		{"short nonce", func(p *SiweMessageParams) { p.Nonce = "abc" }},
+		{"nonce with another line", func(p *SiweMessageParams) { p.Nonce = siweTestNonce + "\nRequest ID: 1" }},
+		{"domain with another line", func(p *SiweMessageParams) { p.Domain = "example.com wants you to sign in\nexample.org" }},
+		{"URI with another line", func(p *SiweMessageParams) { p.URI = "https://example.com\nVersion: 2" }},
+		{"statement with another line", func(p *SiweMessageParams) { p.Statement = "Sign in.\r\nURI: https://example.org" }},
+		{"relative URI", func(p *SiweMessageParams) { p.URI = "login" }},
+		{"bad expiration", func(p *SiweMessageParams) { p.Expiration = "tomorrow" }},
+		{"bad resource", func(p *SiweMessageParams) {
+			p.Resources = []ResourceAbilityRequest{LitPKPResource("*").Request(LitAbilityLitActionExecution)}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := valid
+			tt.modify(&params)
+			if _, err := NewSiweMessage(params); !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("NewSiweMessage() error = %v, want ErrInvalidParams", err)
+			}
+		})
+	}
+}
+
+func TestSiweMessage_AddRecapToMalformedRecap(t *testing.T) {
+	message, err := NewSiweMessage(SiweMessageParams{WalletAddress: siweTestAddress, Nonce: siweTestNonce})
+	if err != nil {
+		t.Fatalf("NewSiweMessage() error = %v", err)
+	}
+	message.Resources = []string{recapURNPrefix + "not-base64!"}
+
+	recap := NewRecap()
+	if err := recap.AddResourceAbilityRequest(LitActionResource("*").Request(LitAbilityLitActionExecution)); err != nil {
+		t.Fatal(err)
+	}
+	if err := message.AddRecap(recap); !errors.Is(err, ErrInvalidParams) {
+		t.Errorf("AddRecap() error = %v, want ErrInvalidParams", err)
+	}
+	if len(message.Resources) != 1 {
+		t.Errorf("Expected no second ReCap, got %v", message.Resources)
+	}
+}
+
+func TestParseSiweMessage_Invalid(t *testing.T) {
+	message, _ := NewSiweMessage(SiweMessageParams{WalletAddress: siweTestAddress, Nonce: siweTestNonce})
+	valid := message.String()
+	tests := map[string]string{
+		"empty":            "",
+		"bad header":       strings.Replace(valid, "wants you", "asks you", 1),
+		"missing nonce":    strings.Replace(valid, "Nonce: ", "Nonsense: ", 1),
+		"bad chain ID":     strings.Replace(valid, "Chain ID: 1", "Chain ID: one", 1),
+		"trailing line":    valid + "\nExtra: field",
+		"bad resource":     valid + "\nResources:\n* urn:recap:",
+		"no blank line":    strings.Replace(valid, DefaultSiweStatement+"\n\n", DefaultSiweStatement+"\n", 1),
+		"out of order URI": strings.Replace(strings.Replace(valid, "URI: https://localhost/login\n", "", 1), "Version: 1", "Version: 1\nURI: https://localhost/login", 1),
+	}
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseSiweMessage(text); !errors.Is(err, ErrInvalidParams) {
+				t.Errorf("ParseSiweMessage() error = %v, want ErrInvalidParams", err)
+			}
+		})
+	}
+
+	// A message without a statement has two blank lines after the address
+	message.Statement = ""
+	parsed, err := ParseSiweMessage(message.String())
+	if err != nil || parsed.Statement != "" || parsed.URI != message.URI {
+		t.Errorf("ParseSiweMessage() without a statement = %+v, %v", parsed, err)
+	}
+}
+
+// jsSiweMessages is the dump written by `npm run siwe` in js-sdk-server
+type jsSiweMessages struct {
+	Version  string `json:"version"`
+	Messages []struct {
+		Name   string `json:"name"`
+		Params struct {
+			URI           string                   `json:"uri"`
+			Expiration    string                   `json:"expiration"`
+			Resources     []ResourceAbilityRequest `json:"resources"`
+			WalletAddress string                   `json:"walletAddress"`
+			Nonce         string                   `json:"nonce"`
+			Domain        string                   `json:"domain"`
+			Statement     string                   `json:"statement"`
+			ChainID       int64                    `json:"chainId"`
+			IssuedAt      string                   `json:"issuedAt"`
+		} `json:"params"`
+		Message string `json:"message"`
+		// Recap is the urn:recap: resource of Message, empty without one
+		Recap string `json:"recap"`
+	} `json:"messages"`
+}
+
+func TestSiweMessageParity(t *testing.T) {
+	data := readParityFile(t, "siwe_messages.json", "siwe")
+	var js jsSiweMessages
+	if err := json.Unmarshal(data, &js); err != nil {
+		t.Fatalf("failed to parse siwe_messages.json: %v", err)
+	}
+	t.Logf("Checking against @lit-protocol/auth-helpers %s", js.Version)
+
+	for _, m := range js.Messages {
+		t.Run(m.Name, func(t *testing.T) {
+			message, err := NewSiweMessage(SiweMessageParams(m.Params))
+			if err != nil {
+				t.Fatalf("NewSiweMessage() error = %v", err)
+			}
+			if got := message.String(); got != m.Message {
+				t.Errorf("String() = %s\nwant %s", got, m.Message)
+			}
+			var recap string
+			if n := len(message.Resources); n > 0 && strings.HasPrefix(message.Resources[n-1], "urn:recap:") {
+				recap = message.Resources[n-1]
+			}
+			if recap != m.Recap {
+				t.Errorf("ReCap URI = %s, want %s", recap, m.Recap)
+			}
+			parsed, err := ParseSiweMessage(m.Message)
+			if err != nil {
+				t.Fatalf("ParseSiweMessage() error = %v", err)
+			}
+			if !reflect.DeepEqual(parsed, message) {
+				t.Errorf("ParseSiweMessage() = %+v, want %+v", parsed, message)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// bridgeSocketEnv is the environment variable telling the server to listen on
+// a Unix socket at the given path instead of a TCP port
+const bridgeSocketEnv = "SOCKET_PATH"
+
+// unixSocketName is the name of the server's socket inside its private directory
+const unixSocketName = "bridge.sock"
+
+// unixSocketBaseURL is the base URL used for requests sent over the Unix
+// socket. The host is never resolved, the transport always dials the socket.
+const unixSocketBaseURL = "http://lit-bridge"
+
+// newUnixSocketPath creates a private directory, only accessible to the
+// current user, and returns the path of a socket inside it
+func newUnixSocketPath() (string, error) {
+	dir, err := os.MkdirTemp("", "lit-bridge-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	// MkdirTemp already uses 0700, but make sure regardless of the platform
+	if err := os.Chmod(dir, 0o700); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to secure socket directory: %w", err)
+	}
+	return filepath.Join(dir, unixSocketName), nil
+}
+
+// unixSocketHTTPClient returns a copy of client whose transport dials the
+// Unix socket at socketPath for every request
+func unixSocketHTTPClient(client *http.Client, socketPath string) (*http.Client, error) {
+	var transport *http.Transport
+	switch t := client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		return nil, fmt.Errorf("a Unix socket needs the HTTP client's Transport to be an *http.Transport, got %T", t)
+	}
+
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", socketPath)
+	}
+
+	socketClient := *client
+	socketClient.Transport = transport
+	return &socketClient, nil
+}
@@ -0,0 +1,65 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestLitNodeClient_UnixSocket(t *testing.T) {
+	client, err := NewLitNodeClient(append(fakeBridgeOptions(t), WithUnixSocket())...)
+	if err != nil {
+		t.Fatalf("NewLitNodeClient() error = %v", err)
+	}
+	defer client.Close()
+
+	if client.socketPath == "" {
+		t.Fatal("Expected the client to use a Unix socket")
+	}
+	if client.port != 0 {
+		t.Errorf("port = %d, want 0 when using a Unix socket", client.port)
+	}
+
+	dir := filepath.Dir(client.socketPath)
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatalf("failed to stat socket directory: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o700 {
+		t.Errorf("socket directory mode = %v, want 0700", info.Mode().Perm())
+	}
+
+	if _, err := client.ConnectContext(context.Background()); err != nil {
+		t.Fatalf("ConnectContext() error = %v", err)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected the socket directory to be removed by Close, stat error = %v", err)
+	}
+}
+
+func TestLitNodeClient_UnixSocketsAreIsolated(t *testing.T) {
+	first, err := NewLitNodeClient(append(fakeBridgeOptions(t), WithUnixSocket())...)
+	if err != nil {
+		t.Fatalf("NewLitNodeClient() error = %v", err)
+	}
+	defer first.Close()
+
+	second, err := NewLitNodeClient(append(fakeBridgeOptions(t), WithUnixSocket())...)
+	if err != nil {
+		t.Fatalf("NewLitNodeClient() error = %v", err)
+	}
+	defer second.Close()
+
+	if first.socketPath == second.socketPath {
+		t.Fatalf("Expected each client to get its own socket, both use %s", first.socketPath)
+	}
+	if first.Server() == second.Server() {
+		t.Fatal("Expected each client to start its own server")
+	}
+}
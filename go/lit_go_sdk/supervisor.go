@@ -0,0 +1,222 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultRestartMinBackoff is the delay before the first restart attempt after a crash
+	defaultRestartMinBackoff = 500 * time.Millisecond
+	// defaultRestartMaxBackoff caps the delay between failed restart attempts
+	defaultRestartMaxBackoff = 30 * time.Second
+)
+
+// errClientClosed is returned by requests made after the client was closed
+var errClientClosed = errors.New("lit: client closed")
+
+// replayEndpoints are the endpoints whose effect lives in the server's
+// app.locals, in the order they are replayed after a restart
+var replayEndpoints = []string{
+	"/litNodeClient/new",
+	"/setSigner",
+	"/litContractsClient/new",
+	"/litNodeClient/connect",
+}
+
+// bridgeState records the last successful request to each of the
+// replayEndpoints so the server's state can be rebuilt after a restart
+type bridgeState struct {
+	mu       sync.Mutex
+	payloads map[string]interface{}
+}
+
+// record remembers payload if endpoint changes state that must be replayed
+func (s *bridgeState) record(endpoint string, payload interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if endpoint == "/litNodeClient/disconnect" {
+		delete(s.payloads, "/litNodeClient/connect")
+		return
+	}
+	for _, replayed := range replayEndpoints {
+		if endpoint == replayed {
+			if s.payloads == nil {
+				s.payloads = make(map[string]interface{})
+			}
+			s.payloads[endpoint] = payload
+			return
+		}
+	}
+}
+
+// replay sends the recorded requests, in order, with send
+func (s *bridgeState) replay(ctx context.Context, send func(context.Context, string, interface{}) (map[string]interface{}, error)) error {
+	s.mu.Lock()
+	payloads := make(map[string]interface{}, len(s.payloads))
+	for endpoint, payload := range s.payloads {
+		payloads[endpoint] = payload
+	}
+	s.mu.Unlock()
+
+	for _, endpoint := range replayEndpoints {
+		payload, ok := payloads[endpoint]
+		if !ok {
+			continue
+		}
+		if _, err := send(ctx, endpoint, payload); err != nil {
+			return fmt.Errorf("failed to replay %s: %w", endpoint, err)
+		}
+	}
+	return nil
+}
+
+// supervisor restarts the Node.js server when it exits unexpectedly and
+// replays the client state into the new process
+type supervisor struct {
+	client         *LitNodeClient
+	server         *NodeServer
+	minBackoff     time.Duration
+	maxBackoff     time.Duration
+	startupTimeout time.Duration
+
+	mu     sync.Mutex
+	ready  chan struct{} // closed while the server is up
+	closed chan struct{}
+}
+
+// newSupervisor creates a supervisor for server. It must be called before the
+// server is started, and run once it is up.
+func newSupervisor(client *LitNodeClient, server *NodeServer, options clientOptions) *supervisor {
+	ready := make(chan struct{})
+	close(ready)
+	s := &supervisor{
+		client:         client,
+		server:         server,
+		minBackoff:     options.restartMinBackoff,
+		maxBackoff:     options.restartMaxBackoff,
+		startupTimeout: options.startupTimeout,
+		ready:          ready,
+		closed:         make(chan struct{}),
+	}
+	server.onCrash = s.markDown
+	return s
+}
+
+// markDown makes new requests wait until the server has been restarted. It is
+// called as soon as the process exits, before Done is closed.
+func (s *supervisor) markDown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	select {
+	case <-s.ready:
+		s.ready = make(chan struct{})
+	default:
+	}
+}
+
+// run watches the server process until the supervisor is closed
+func (s *supervisor) run() {
+	for {
+		select {
+		case <-s.closed:
+			return
+		case <-s.server.Done():
+		}
+		if s.isClosed() {
+			return
+		}
+
+		s.mu.Lock()
+		ready := s.ready
+		s.mu.Unlock()
+
+		if !s.restart() {
+			return
+		}
+		close(ready)
+	}
+}
+
+// restart starts the server again, retrying with exponential backoff until it
+// comes up with its state replayed. It returns false if the supervisor was
+// closed first.
+func (s *supervisor) restart() bool {
+	backoff := s.minBackoff
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-s.closed:
+			return false
+		case <-time.After(backoff):
+		}
+
+		fmt.Fprintf(s.server.logs, "\n=== Restarting Node.js Server (attempt %d) ===\n", attempt)
+		err := s.start()
+		if err == nil {
+			return true
+		}
+		fmt.Fprintf(s.server.logs, "\n=== Node.js Server Restart Failed ===\nError: %v\n", err)
+
+		backoff *= 2
+		if backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+}
+
+// start runs one restart attempt
+func (s *supervisor) start() error {
+	if err := s.server.Start(); err != nil {
+		return err
+	}
+	if err := s.client.waitForServer(s.startupTimeout); err != nil {
+		s.server.Stop()
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.startupTimeout)
+	defer cancel()
+	if err := s.client.state.replay(ctx, s.client.send); err != nil {
+		s.server.Stop()
+		return err
+	}
+	return nil
+}
+
+// awaitReady blocks while the server is being restarted
+func (s *supervisor) awaitReady(ctx context.Context) error {
+	s.mu.Lock()
+	ready := s.ready
+	s.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-s.closed:
+		return errClientClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// close stops the supervisor from restarting the server
+func (s *supervisor) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.isClosed() {
+		close(s.closed)
+	}
+}
+
+func (s *supervisor) isClosed() bool {
+	select {
+	case <-s.closed:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,69 @@
+package lit_go_sdk
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSupervisor_RestartsAndReplaysState(t *testing.T) {
+	opts := append(fakeBridgeOptions(t), WithRestartBackoff(10*time.Millisecond, 100*time.Millisecond))
+	client, err := NewLitNodeClient(opts...)
+	if err != nil {
+		t.Fatalf("NewLitNodeClient() error = %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := client.NewContext(ctx, LitNodeClientConfig{LitNetwork: "datil-test"}); err != nil {
+		t.Fatalf("NewContext() error = %v", err)
+	}
+	if _, err := client.SetAuthTokenContext(ctx, "0x"+strings.Repeat("11", 32)); err != nil {
+		t.Fatalf("SetAuthTokenContext() error = %v", err)
+	}
+	if _, err := client.ConnectContext(ctx); err != nil {
+		t.Fatalf("ConnectContext() error = %v", err)
+	}
+
+	exited := client.server.Done()
+	client.send(ctx, "/crash", nil)
+	select {
+	case <-exited:
+	case <-ctx.Done():
+		t.Fatal("Timed out waiting for the server to crash")
+	}
+
+	// The next request waits for the restart and sees the replayed state
+	result, err := client.GetPropertyContext(ctx, "calls")
+	if err != nil {
+		t.Fatalf("GetPropertyContext() error = %v", err)
+	}
+	want := []interface{}{"/litNodeClient/new", "/setSigner", "/litNodeClient/connect"}
+	if !reflect.DeepEqual(result["property"], want) {
+		t.Errorf("Replayed calls = %v, want %v", result["property"], want)
+	}
+}
+
+func TestSupervisor_DisconnectIsNotReplayedAsConnect(t *testing.T) {
+	var state bridgeState
+	state.record("/litNodeClient/new", LitNodeClientConfig{LitNetwork: "datil-dev"})
+	state.record("/litNodeClient/connect", nil)
+	state.record("/litNodeClient/executeJs", ExecuteJsParams{})
+	state.record("/litNodeClient/disconnect", nil)
+
+	var replayed []string
+	err := state.replay(context.Background(), func(ctx context.Context, endpoint string, payload interface{}) (map[string]interface{}, error) {
+		replayed = append(replayed, endpoint)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("replay() error = %v", err)
+	}
+	if want := []string{"/litNodeClient/new"}; !reflect.DeepEqual(replayed, want) {
+		t.Errorf("Replayed %v, want %v", replayed, want)
+	}
+}
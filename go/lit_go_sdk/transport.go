@@ -0,0 +1,61 @@
+package lit_go_sdk
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+)
+
+// Transport carries requests from a LitNodeClient to the JS SDK server. The
+// default transport sends them over HTTP to the server started or reused by
+// the client. Set another one with WithTransport, for example the in-process
+// fake in the littest package.
+type Transport interface {
+	// Post sends the JSON encoded body to endpoint, e.g. /litNodeClient/connect,
+	// and returns the HTTP status code and body of the response. The body is
+	// empty for requests without a payload. Errors are only returned when no
+	// response could be obtained; error responses are reported through the
+	// status code and decoded by the client.
+	Post(ctx context.Context, endpoint string, body []byte) (statusCode int, respBody []byte, err error)
+}
+
+// httpTransport is the default transport as a Transport, for wrappers set
+// with WithTransportWrapper
+type httpTransport struct {
+	client *LitNodeClient
+}
+
+// Post implements Transport
+func (t httpTransport) Post(ctx context.Context, endpoint string, body []byte) (int, []byte, error) {
+	return t.client.postHTTP(ctx, endpoint, body)
+}
+
+// roundTrip sends a request through the client's transport
+func (c *LitNodeClient) roundTrip(ctx context.Context, endpoint string, body []byte) (int, []byte, error) {
+	if c.transport != nil {
+		return c.transport.Post(ctx, endpoint, body)
+	}
+	return c.postHTTP(ctx, endpoint, body)
+}
+
+// postHTTP is the default transport, sending the request to the server over HTTP
+func (c *LitNodeClient) postHTTP(ctx context.Context, endpoint string, body []byte) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(endpoint), bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, err
+	}
+	c.setHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, respBody, nil
+}
@@ -8,14 +8,26 @@ GOPROXY=proxy.golang.org go list -versions -m github.com/lit-protocol/lit-polygl
 read -p "Enter version number (without v prefix, e.g. 0.1.0): " VERSION_INPUT
 VERSION="v$VERSION_INPUT"
 
+# Build the JS SDK server bundle that is embedded into the module. The Go
+# module proxy serves the tagged tree, so the bundle must be committed
+# before tagging or `go get` users embed nothing.
+(cd ../js-sdk-server && npm ci && npm run build) || exit 1
+if [ ! -s lit_go_sdk/bundle/bundled_server.js ]; then
+    echo "lit_go_sdk/bundle/bundled_server.js was not built" >&2
+    exit 1
+fi
+
 cd lit_go_sdk
 
 # Update go.mod and go.sum
 go mod tidy
 
-# Commit any changes to go.mod and go.sum
-git add go.mod go.sum
-git commit -m "chore: update go dependencies for $VERSION" || true
+# Check that the bundle is embedded before publishing it
+LIT_GO_SDK_REQUIRE_BUNDLE=1 go test -run TestBundledServerScript . || exit 1
+
+# Commit the bundle and any changes to go.mod and go.sum
+git add go.mod go.sum bundle/bundled_server.js
+git commit -m "chore: update go dependencies and server bundle for $VERSION" || true
 
 # Create and push the tag
 git tag "go/lit_go_sdk/$VERSION"
@@ -0,0 +1,37 @@
+// Dumps the @lit-protocol/constants values the Go SDK mirrors to
+// go/lit_go_sdk/testdata/lit_constants.json, for its TestConstantsParity. The
+// dump is not committed: CI runs this script before the Go tests with
+// LIT_GO_SDK_REQUIRE_PARITY set, so the test fails there if it is missing.
+// Locally the test is skipped until `npm run constants` has been run.
+import { mkdirSync, readFileSync, writeFileSync } from 'fs';
+import { dirname, join } from 'path';
+import {
+  AUTH_METHOD_SCOPE,
+  AUTH_METHOD_TYPE,
+  LIT_ABILITY,
+  LIT_CHAINS,
+  LIT_NETWORK,
+  LIT_RESOURCE_PREFIX,
+} from '@lit-protocol/constants';
+
+const { version } = JSON.parse(
+  readFileSync(
+    join(__dirname, '../node_modules/@lit-protocol/constants/package.json'),
+    'utf8'
+  )
+);
+
+const constants = {
+  version,
+  LIT_NETWORK,
+  AUTH_METHOD_TYPE,
+  AUTH_METHOD_SCOPE,
+  LIT_ABILITY,
+  LIT_RESOURCE_PREFIX,
+  LIT_CHAINS: Object.keys(LIT_CHAINS).sort(),
+};
+
+const out = join(__dirname, '../../go/lit_go_sdk/testdata/lit_constants.json');
+mkdirSync(dirname(out), { recursive: true });
+writeFileSync(out, JSON.stringify(constants, null, 2) + '\n');
+console.log(`Wrote @lit-protocol/constants ${version} to ${out}`);
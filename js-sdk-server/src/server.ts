@@ -1,5 +1,7 @@
 import express, { Request, Response, NextFunction } from 'express';
 import bodyParser from 'body-parser';
+import { AsyncLocalStorage } from 'async_hooks';
+import { randomUUID, timingSafeEqual } from 'crypto';
 import { LitNodeClientNodeJs } from '@lit-protocol/lit-node-client-nodejs';
 import {
   LIT_NETWORKS,
@@ -9,6 +11,7 @@ import {
   AUTH_METHOD_TYPE,
   PROVIDER_TYPE,
   LIT_NETWORK,
+  version as litSdkVersion,
 } from '@lit-protocol/constants';
 import { ethers } from 'ethers';
 import {
@@ -53,13 +56,17 @@ interface GetSessionSigsRequest {
   resourceAbilityRequests: ResourceAbilityRequest[];
 }
 
+const RESPONSE_STRATEGIES = ['leastCommon', 'mostCommon', 'custom'];
+
 interface ExecuteJsRequest {
   authMethods?: any[];
-  code: string;
+  code?: string;
   ipfsId?: string;
-  ipfsOptions?: any;
+  ipfsOptions?: { overwriteCode?: boolean; gatewayUrl?: string };
   jsParams?: any;
-  responseStrategy?: string;
+  responseStrategy?: {
+    strategy: 'leastCommon' | 'mostCommon' | 'custom';
+  };
   sessionSigs?: any;
   useSingleNode?: boolean;
 }
@@ -72,7 +79,9 @@ interface PkpSignRequest {
 }
 
 interface LitContractsClientNewRequest {
-  privateKey: string;
+  // The Go SDK sends the address of its signer, the Python SDK a private key
+  privateKey?: string;
+  address?: string;
   litNodeClient?: any;
   network: keyof typeof LIT_NETWORKS;
   debug?: boolean;
@@ -80,16 +89,29 @@ interface LitContractsClientNewRequest {
 
 interface MintWithAuthRequest {
   authMethod: {
-    authMethodType: (typeof AUTH_METHOD_SCOPE)[keyof typeof AUTH_METHOD_SCOPE];
+    authMethodType: (typeof AUTH_METHOD_TYPE)[keyof typeof AUTH_METHOD_TYPE];
     accessToken: string;
   };
   scopes: (typeof AUTH_METHOD_SCOPE)[keyof typeof AUTH_METHOD_SCOPE][];
+  // The public key of the credential, required for WebAuthn
+  pubkey?: string;
 }
 
 interface SetAuthTokenRequest {
   authToken: string;
 }
 
+interface SetSignerRequest {
+  address: string;
+}
+
+interface SignerRespondRequest {
+  id: string;
+  messageSignature?: string;
+  signedTransaction?: string;
+  error?: string;
+}
+
 interface CreateSiweMessageRequest {
   uri: string;
   expiration: string;
@@ -128,23 +150,263 @@ const asyncHandler =
     return Promise.resolve(fn(req, res, next)).catch(next);
   };
 
+// Signer callbacks
+//
+// The Go SDK keeps its wallet key in its own process. Requests that need a
+// signature from it are run as signer operations: when the wallet is asked to
+// sign, the pending HTTP response carries a signatureRequest instead of the
+// result, and the operation resumes when the SDK answers it on
+// /signer/respond. The response to /signer/respond is the next step of the
+// operation, until its result is sent.
+
+// How long an operation waits for the SDK to answer a signature request
+const SIGNATURE_TIMEOUT_MS = 2 * 60 * 1000;
+
+interface SignatureRequest {
+  id: string;
+  // Hex encoded message to sign as an EIP-191 personal message
+  message?: string;
+  // Populated transaction to sign, with hex encoded numbers
+  transaction?: Record<string, string | undefined>;
+}
+
+type OperationEvent =
+  | { kind: 'signatureRequest'; request: SignatureRequest }
+  | { kind: 'result'; result: any }
+  | { kind: 'error'; error: any };
+
+// Thrown by signer operations when a precondition fails, to answer 400 like
+// the other handlers do
+class BadRequestError extends Error {}
+
+class SignerOperation {
+  readonly id = randomUUID();
+  private events: OperationEvent[] = [];
+  private waiting?: (event: OperationEvent) => void;
+  private pending?: {
+    resolve: (response: SignerRespondRequest) => void;
+    reject: (error: Error) => void;
+    timer: NodeJS.Timeout;
+  };
+
+  push(event: OperationEvent) {
+    const waiting = this.waiting;
+    if (waiting) {
+      this.waiting = undefined;
+      waiting(event);
+    } else {
+      this.events.push(event);
+    }
+  }
+
+  next(): Promise<OperationEvent> {
+    const event = this.events.shift();
+    if (event) {
+      return Promise.resolve(event);
+    }
+    return new Promise((resolve) => (this.waiting = resolve));
+  }
+
+  requestSignature(
+    request: Omit<SignatureRequest, 'id'>
+  ): Promise<SignerRespondRequest> {
+    return new Promise((resolve, reject) => {
+      const timer = setTimeout(() => {
+        this.pending = undefined;
+        operations.delete(this.id);
+        reject(new Error('Timed out waiting for the SDK to sign'));
+      }, SIGNATURE_TIMEOUT_MS);
+      this.pending = { resolve, reject, timer };
+      operations.set(this.id, this);
+      this.push({
+        kind: 'signatureRequest',
+        request: { id: this.id, ...request },
+      });
+    });
+  }
+
+  answer(response: SignerRespondRequest) {
+    const pending = this.pending;
+    if (!pending) {
+      return;
+    }
+    clearTimeout(pending.timer);
+    this.pending = undefined;
+    operations.delete(this.id);
+    if (response.error) {
+      pending.reject(new Error(`SDK signer failed: ${response.error}`));
+    } else {
+      pending.resolve(response);
+    }
+  }
+}
+
+// Operations waiting for the SDK to answer a signature request, by ID
+const operations = new Map<string, SignerOperation>();
+// The operation the current request runs in
+const currentOperation = new AsyncLocalStorage<SignerOperation>();
+
+const requestSignature = (
+  request: Omit<SignatureRequest, 'id'>
+): Promise<SignerRespondRequest> => {
+  const operation = currentOperation.getStore();
+  if (!operation) {
+    throw new Error('The SDK signer can only sign in a signer operation');
+  }
+  return operation.requestSignature(request);
+};
+
+// Sends the next event of an operation as the response to res
+const respondWithNextEvent = async (
+  operation: SignerOperation,
+  res: Response,
+  next: NextFunction
+) => {
+  const event = await operation.next();
+  switch (event.kind) {
+    case 'signatureRequest':
+      res.json({ signatureRequest: event.request });
+      break;
+    case 'result':
+      res.json(event.result);
+      break;
+    case 'error':
+      if (event.error instanceof BadRequestError) {
+        res.status(400).json({ success: false, error: event.error.message });
+      } else {
+        next(event.error);
+      }
+  }
+};
+
+// Like asyncHandler, for handlers that may sign with the SDK signer. fn
+// returns the response body instead of sending it.
+const signerHandler =
+  (fn: (req: Request) => Promise<any>) =>
+  (req: Request, res: Response, next: NextFunction) => {
+    const operation = new SignerOperation();
+    currentOperation.run(operation, () => {
+      fn(req).then(
+        (result) => operation.push({ kind: 'result', result }),
+        (error) => operation.push({ kind: 'error', error })
+      );
+    });
+    respondWithNextEvent(operation, res, next).catch(next);
+  };
+
+// An ethers Signer for the wallet held by the SDK. It asks the SDK to sign,
+// so it can only sign in a signer operation.
+class SdkSigner extends ethers.Signer {
+  readonly provider?: ethers.providers.Provider;
+
+  constructor(
+    private readonly address: string,
+    provider?: ethers.providers.Provider
+  ) {
+    super();
+    ethers.utils.defineReadOnly(this, 'provider', provider);
+  }
+
+  async getAddress(): Promise<string> {
+    return this.address;
+  }
+
+  async signMessage(message: ethers.utils.Bytes | string): Promise<string> {
+    const bytes =
+      typeof message === 'string' ? ethers.utils.toUtf8Bytes(message) : message;
+    const { messageSignature } = await requestSignature({
+      message: ethers.utils.hexlify(bytes),
+    });
+    return messageSignature!;
+  }
+
+  async signTransaction(
+    transaction: ethers.providers.TransactionRequest
+  ): Promise<string> {
+    const tx = await ethers.utils.resolveProperties(transaction);
+    const hex = (value?: ethers.BigNumberish | null) =>
+      value == null ? undefined : ethers.utils.hexValue(value);
+    const { signedTransaction } = await requestSignature({
+      transaction: {
+        type: hex(tx.type ?? 0),
+        chainId: hex(tx.chainId),
+        nonce: hex(tx.nonce),
+        to: tx.to,
+        value: hex(tx.value ?? 0),
+        data: ethers.utils.hexlify(tx.data ?? '0x'),
+        gasLimit: hex(tx.gasLimit),
+        gasPrice: hex(tx.gasPrice),
+        maxFeePerGas: hex(tx.maxFeePerGas),
+        maxPriorityFeePerGas: hex(tx.maxPriorityFeePerGas),
+      },
+    });
+    return signedTransaction!;
+  }
+
+  connect(provider: ethers.providers.Provider): SdkSigner {
+    return new SdkSigner(this.address, provider);
+  }
+}
+
+// Version of the protocol spoken with the Go and Python SDKs, reported by
+// /handshake. The Go SDK refuses to reuse a server reporting another version,
+// so bump it together with BridgeVersion in go/lit_go_sdk/handshake.go.
+const BRIDGE_VERSION = '1.1.0';
+// Identifies this process, so SDKs can tell restarted servers apart
+const instanceId = randomUUID();
+
 const app = express();
-const port = 3092;
+const port = Number(process.env.PORT) || 3092;
+// When set, listen on this Unix domain socket instead of the TCP port. The
+// SDK creates it in a directory only the current user can access.
+const socketPath = process.env.SOCKET_PATH;
+
+// The SDK that launched this server passes a per-launch secret which every
+// request must carry as a bearer token. Without it any local process or
+// browser page could use the wallet held by this server.
+const bridgeSecret = process.env.LIT_BRIDGE_SECRET;
+if (!bridgeSecret) {
+  console.warn(
+    'LIT_BRIDGE_SECRET is not set: requests to this server are not authenticated'
+  );
+}
 
-// Middleware
-app.use(bodyParser.json());
+const isAuthorized = (req: Request): boolean => {
+  if (!bridgeSecret) {
+    return true;
+  }
+  const provided = Buffer.from(req.get('Authorization') ?? '');
+  const expected = Buffer.from(`Bearer ${bridgeSecret}`);
+  return (
+    provided.length === expected.length && timingSafeEqual(provided, expected)
+  );
+};
+
+// Requests from web pages carry an Origin or Sec-Fetch-Site header, even the
+// no-cors POSTs a page can send without reading the response. The SDKs never
+// send them, so refuse these requests even when there is no secret.
+const isFromBrowser = (req: Request): boolean =>
+  req.get('Origin') !== undefined || req.get('Sec-Fetch-Site') !== undefined;
 
-// Enable CORS for localhost development
+// Middleware
 app.use((req: Request, res: Response, next: NextFunction) => {
-  res.header('Access-Control-Allow-Origin', '*');
-  res.header(
-    'Access-Control-Allow-Headers',
-    'Origin, X-Requested-With, Content-Type, Accept'
-  );
-  res.header('Access-Control-Allow-Methods', 'GET, POST, OPTIONS');
+  if (isFromBrowser(req)) {
+    return res.status(403).json({
+      success: false,
+      error: 'Requests from browsers are not accepted',
+    });
+  }
+  if (!isAuthorized(req)) {
+    return res.status(401).json({
+      success: false,
+      error: 'Unauthorized',
+    });
+  }
   next();
 });
 
+app.use(bodyParser.json());
+
 // Create a new LitNodeClient
 app.post(
   '/litNodeClient/new',
@@ -159,13 +421,8 @@ app.post(
       await app.locals.litNodeClient.connect();
 
       if (app.locals.litContractClient) {
-        // create a new lit contracts client with this same config
-        app.locals.litContractClient = new LitContracts({
-          signer: app.locals.ethersWallet,
-          network: app.locals.litNodeClient.config.litNetwork,
-          debug: true,
-        });
-        await app.locals.litContractClient.connect();
+        // Recreated for this network the next time it is used
+        app.locals.litContractClient = undefined;
       }
 
       res.json({ success: true });
@@ -219,19 +476,15 @@ app.post(
 
 app.post(
   '/litNodeClient/getSessionSigs',
-  asyncHandler(
-    async (req: Request<{}, {}, GetSessionSigsRequest>, res: Response) => {
+  signerHandler(
+    async (req: Request<{}, {}, GetSessionSigsRequest>) => {
       if (!app.locals.litNodeClient) {
-        return res.status(400).json({
-          success: false,
-          error: 'LitNodeClient not initialized',
-        });
+        throw new BadRequestError('LitNodeClient not initialized');
       }
       if (!app.locals.ethersWallet) {
-        return res.status(400).json({
-          success: false,
-          error: 'Ethers wallet not initialized - Please set a Lit auth token.',
-        });
+        throw new BadRequestError(
+          'Ethers wallet not initialized - Please set a Lit auth token.'
+        );
       }
 
       console.log('req.body for getSessionSigs', req.body);
@@ -273,7 +526,7 @@ app.post(
         },
       });
 
-      res.json({ success: true, sessionSigs });
+      return { success: true, sessionSigs };
     }
   )
 );
@@ -301,13 +554,34 @@ app.post(
         useSingleNode,
       } = req.body;
 
+      if (
+        responseStrategy &&
+        !RESPONSE_STRATEGIES.includes(responseStrategy.strategy)
+      ) {
+        return res.status(400).json({
+          success: false,
+          error: `Unknown response strategy: ${responseStrategy.strategy}`,
+        });
+      }
+
+      // The SDKs run custom filters themselves: the server never evaluates
+      // code from a request, and for a custom strategy it returns every
+      // node's response as a JSON array for the SDK to choose from
+      const strategy = responseStrategy && {
+        strategy: responseStrategy.strategy,
+        customFilter:
+          responseStrategy.strategy === 'custom'
+            ? (responses: unknown[]) => JSON.stringify(responses)
+            : undefined,
+      };
+
       const response = await app.locals.litNodeClient.executeJs({
         authMethods,
         code,
         ipfsId,
         ipfsOptions,
         jsParams,
-        responseStrategy,
+        responseStrategy: strategy,
         sessionSigs,
         useSingleNode,
       });
@@ -339,6 +613,26 @@ app.post(
   })
 );
 
+// contractsClient returns the LitContracts client, creating one for the
+// wallet and the LitNodeClient's network if there is none, and connects it
+// the first time it is used
+async function contractsClient(): Promise<LitContracts> {
+  if (!app.locals.litContractClient) {
+    if (!app.locals.ethersWallet) {
+      throw new BadRequestError('LitContractsClient not initialized');
+    }
+    app.locals.litContractClient = new LitContracts({
+      signer: app.locals.ethersWallet,
+      network: app.locals.litNodeClient?.config.litNetwork,
+    });
+  }
+  const client: LitContracts = app.locals.litContractClient;
+  if (!client.connected) {
+    await client.connect();
+  }
+  return client;
+}
+
 // Create a new LitContracts client
 app.post(
   '/litContractsClient/new',
@@ -347,11 +641,13 @@ app.post(
       req: Request<{}, {}, LitContractsClientNewRequest>,
       res: Response
     ) => {
-      const { privateKey, litNodeClient, network, debug } = req.body;
-      app.locals.ethersWallet = new ethers.Wallet(
-        privateKey,
-        new ethers.providers.JsonRpcProvider(LIT_RPC.CHRONICLE_YELLOWSTONE)
+      const { privateKey, address, network, debug } = req.body;
+      const provider = new ethers.providers.JsonRpcProvider(
+        LIT_RPC.CHRONICLE_YELLOWSTONE
       );
+      app.locals.ethersWallet = privateKey
+        ? new ethers.Wallet(privateKey, provider)
+        : new SdkSigner(ethers.utils.getAddress(address!), provider);
       app.locals.litContractClient = new LitContracts({
         signer: app.locals.ethersWallet,
         network,
@@ -366,22 +662,17 @@ app.post(
 // Mint a new PKP with an auth method
 app.post(
   '/litContractsClient/mintWithAuth',
-  asyncHandler(
-    async (req: Request<{}, {}, MintWithAuthRequest>, res: Response) => {
-      if (!app.locals.litContractClient) {
-        return res.status(400).json({
-          success: false,
-          error: 'LitContractsClient not initialized',
-        });
-      }
-
-      const { authMethod, scopes } = req.body;
+  signerHandler(
+    async (req: Request<{}, {}, MintWithAuthRequest>) => {
+      const client = await contractsClient();
+      const { authMethod, scopes, pubkey } = req.body;
       console.log('req.body for mintWithAuth', req.body);
-      const mintInfo = await app.locals.litContractClient.mintWithAuth({
+      const mintInfo = await client.mintWithAuth({
         authMethod,
         scopes,
+        pubkey,
       });
-      res.json(mintInfo);
+      return mintInfo;
     }
   )
 );
@@ -415,46 +706,71 @@ app.post(
 
 app.post(
   '/authHelpers/generateAuthSig',
-  asyncHandler(
-    async (req: Request<{}, {}, GenerateAuthSigRequest>, res: Response) => {
+  signerHandler(
+    async (req: Request<{}, {}, GenerateAuthSigRequest>) => {
       if (!app.locals.ethersWallet) {
-        return res.status(400).json({
-          success: false,
-          error: 'Ethers wallet not initialized',
-        });
+        throw new BadRequestError('Ethers wallet not initialized');
       }
       const { toSign } = req.body;
       const authSig = await generateAuthSig({
         signer: app.locals.ethersWallet,
         toSign,
       });
-      res.json({ success: true, authSig });
+      return { success: true, authSig };
     }
   )
 );
 
+// Answer a signature request of a signer operation, and send its next step
+app.post(
+  '/signer/respond',
+  (
+    req: Request<{}, {}, SignerRespondRequest>,
+    res: Response,
+    next: NextFunction
+  ) => {
+    const operation = operations.get(req.body.id);
+    if (!operation) {
+      return res.status(400).json({
+        success: false,
+        error: 'Unknown or expired signature request',
+      });
+    }
+    operation.answer(req.body);
+    respondWithNextEvent(operation, res, next).catch(next);
+  }
+);
+
 // set the wallet used to talk to the Lit Nodes
 app.post(
   '/setAuthToken',
   asyncHandler(
     async (req: Request<{}, {}, SetAuthTokenRequest>, res: Response) => {
-      if (!app.locals.litNodeClient) {
-        return res.status(400).json({
-          success: false,
-          error: 'LitNodeClient not initialized',
-        });
-      }
       const { authToken } = req.body;
       app.locals.ethersWallet = new ethers.Wallet(
         authToken,
         new ethers.providers.JsonRpcProvider(LIT_RPC.CHRONICLE_YELLOWSTONE)
       );
-      app.locals.litContractClient = new LitContracts({
-        signer: app.locals.ethersWallet,
-        network: app.locals.litNodeClient.config.litNetwork,
-        debug: true,
-      });
-      await app.locals.litContractClient.connect();
+      // Created for the new wallet when it is first used, see contractsClient
+      app.locals.litContractClient = undefined;
+      res.json({ success: true });
+    }
+  )
+);
+
+// Set the wallet used to talk to the Lit Nodes to one held by the SDK, which
+// signs for it in signer operations
+app.post(
+  '/setSigner',
+  asyncHandler(
+    async (req: Request<{}, {}, SetSignerRequest>, res: Response) => {
+      const { address } = req.body;
+      app.locals.ethersWallet = new SdkSigner(
+        ethers.utils.getAddress(address),
+        new ethers.providers.JsonRpcProvider(LIT_RPC.CHRONICLE_YELLOWSTONE)
+      );
+      // Created for the new wallet when it is first used, see contractsClient
+      app.locals.litContractClient = undefined;
       res.json({ success: true });
     }
   )
@@ -552,10 +868,62 @@ app.post('/isReady', (req: Request, res: Response) => {
   }
 });
 
+// Identifies this server so the SDKs can check they are talking to a bridge
+// they understand, started by the same user
+// Reads the version of a bundled package, or 'unknown' if its package.json
+// is not available
+const packageVersion = (load: () => { version?: string }): string => {
+  try {
+    return load().version ?? 'unknown';
+  } catch {
+    return 'unknown';
+  }
+};
+
+// The requires are literal so esbuild bundles each package.json
+const litPackageVersions = {
+  '@lit-protocol/lit-node-client-nodejs': packageVersion(() =>
+    require('@lit-protocol/lit-node-client-nodejs/package.json')
+  ),
+  '@lit-protocol/auth-helpers': packageVersion(() =>
+    require('@lit-protocol/auth-helpers/package.json')
+  ),
+  '@lit-protocol/contracts-sdk': packageVersion(() =>
+    require('@lit-protocol/contracts-sdk/package.json')
+  ),
+  '@lit-protocol/constants': litSdkVersion,
+};
+
+app.post('/handshake', (req: Request, res: Response) => {
+  res.json({
+    bridgeVersion: BRIDGE_VERSION,
+    packages: {
+      ...litPackageVersions,
+      node: process.versions.node,
+    },
+    instanceId,
+    pid: process.pid,
+    uid: process.getuid ? process.getuid() : -1,
+  });
+});
+
+// Lets an SDK replace a server it cannot use. Requests are authenticated, so
+// only holders of the bridge secret can stop the server.
+app.post('/shutdown', (req: Request, res: Response) => {
+  res.json({ success: true });
+  res.on('finish', () => shutdown('Received shutdown request'));
+});
+
 // Error-handling middleware
 app.use(
   (
-    error: Error & { status?: number },
+    error: Error & {
+      status?: number;
+      code?: string;
+      kind?: string;
+      errorCode?: string;
+      errorKind?: string;
+    },
     req: Request,
     res: Response,
     next: NextFunction
@@ -570,15 +938,37 @@ app.use(
       error: {
         message: error.message,
         stack: error.stack,
+        // Lit errors carry a code (e.g. NodeError, InvalidSessionSigs) and a kind
+        code:
+          error.errorCode ??
+          error.code ??
+          (error.name !== 'Error' ? error.name : undefined),
+        kind: error.errorKind ?? error.kind,
       },
     });
   }
 );
 
-app.listen(port, async () => {
+const server = app.listen(socketPath || port, async () => {
   app.locals.litNodeClient = new LitNodeClientNodeJs({
     litNetwork: LIT_NETWORK.DatilDev,
   });
   await app.locals.litNodeClient.connect();
-  console.log(`Server is running at http://localhost:${port}`);
+  console.log(
+    socketPath
+      ? `Server is running at unix:${socketPath}`
+      : `Server is running at http://localhost:${port}`
+  );
 });
+
+// Shut down cleanly when the Go or Python SDK stops the server
+function shutdown(reason: string) {
+  console.log(`${reason}, shutting down`);
+  if (app.locals.litNodeClient) {
+    app.locals.litNodeClient.disconnect();
+  }
+  server.close(() => process.exit(0));
+  // Connections kept alive by clients would otherwise hold the process open
+  server.closeAllConnections();
+}
+process.on('SIGTERM', () => shutdown('Received SIGTERM'));
@@ -0,0 +1,136 @@
+// Dumps SIWE messages made by @lit-protocol/auth-helpers createSiweMessage
+// to go/lit_go_sdk/testdata/siwe_messages.json, so the Go SDK's
+// TestSiweMessageParity can check that NewSiweMessage makes the same bytes
+// and ReCap URI. Like the constants dump, it is not committed: CI runs this
+// script before the Go tests with LIT_GO_SDK_REQUIRE_PARITY set, and locally
+// the test is skipped until `npm run siwe` has been run.
+import { mkdirSync, readFileSync, writeFileSync } from 'fs';
+import { dirname, join } from 'path';
+import { createSiweMessage } from '@lit-protocol/auth-helpers';
+import { LitNodeClientNodeJs } from '@lit-protocol/lit-node-client-nodejs';
+import { deserializeResourceAbilityRequests } from './utils';
+import { ResourceRequest } from './types';
+
+interface SiweCase {
+  name: string;
+  params: {
+    uri?: string;
+    expiration?: string;
+    resources?: ResourceRequest[];
+    walletAddress: string;
+    nonce: string;
+    domain?: string;
+    statement?: string;
+    chainId?: number;
+  };
+}
+
+const walletAddress = '0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266';
+const nonce =
+  '0x9c22ff5f21f0b81b113e63f7db6da94fedef11b2119b4088b89664fb9a3cb658';
+
+const cases: SiweCase[] = [
+  { name: 'defaults', params: { walletAddress, nonce } },
+  {
+    name: 'session',
+    params: {
+      uri: 'lit:session:6a1f2e3d4c5b6a7980a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f607',
+      expiration: '2030-01-01T00:00:00.000Z',
+      resources: [
+        {
+          resource: { resource: '*', resourcePrefix: 'lit-litaction' },
+          ability: 'lit-action-execution',
+        },
+      ],
+      walletAddress,
+      nonce,
+    },
+  },
+  {
+    name: 'several resources',
+    params: {
+      uri: 'lit:session:abc',
+      expiration: '2030-01-01T00:00:00.000Z',
+      resources: [
+        {
+          resource: { resource: '*', resourcePrefix: 'lit-pkp' },
+          ability: 'pkp-signing',
+        },
+        {
+          resource: { resource: '*', resourcePrefix: 'lit-litaction' },
+          ability: 'lit-action-execution',
+        },
+        {
+          resource: { resource: '*', resourcePrefix: 'lit-accesscontrolcondition' },
+          ability: 'access-control-condition-decryption',
+        },
+        {
+          resource: { resource: '*', resourcePrefix: 'lit-accesscontrolcondition' },
+          ability: 'access-control-condition-signing',
+        },
+      ],
+      walletAddress,
+      nonce,
+    },
+  },
+  {
+    name: 'custom fields',
+    params: {
+      uri: 'https://example.com/login',
+      expiration: '2030-06-15T12:30:00.000Z',
+      resources: [
+        {
+          resource: { resource: '1', resourcePrefix: 'lit-ratelimitincrease' },
+          ability: 'rate-limit-increase-auth',
+        },
+      ],
+      walletAddress,
+      nonce: 'abcdefgh12345678',
+      domain: 'example.com',
+      statement: 'Sign in to Example.',
+      chainId: 175188,
+    },
+  },
+];
+
+const main = async () => {
+  const { version } = JSON.parse(
+    readFileSync(
+      join(__dirname, '../node_modules/@lit-protocol/auth-helpers/package.json'),
+      'utf8'
+    )
+  );
+  // Only used to build the ReCaps, it never connects
+  const litNodeClient = new LitNodeClientNodeJs({
+    litNetwork: 'datil-dev',
+    debug: false,
+  });
+
+  const messages = [];
+  for (const { name, params } of cases) {
+    const message = await createSiweMessage({
+      ...params,
+      resources: params.resources
+        ? deserializeResourceAbilityRequests(params.resources)
+        : undefined,
+      litNodeClient,
+    });
+    // createSiweMessage always issues the message now
+    const issuedAt = message.match(/^Issued At: (.*)$/m)![1];
+    const recap = message.match(/^- (urn:recap:.*)$/m)?.[1] ?? '';
+    messages.push({ name, params: { ...params, issuedAt }, message, recap });
+  }
+
+  const out = join(__dirname, '../../go/lit_go_sdk/testdata/siwe_messages.json');
+  mkdirSync(dirname(out), { recursive: true });
+  writeFileSync(
+    out,
+    JSON.stringify({ version, messages }, null, 2) + '\n'
+  );
+  console.log(`Wrote @lit-protocol/auth-helpers ${version} messages to ${out}`);
+};
+
+main().catch((err) => {
+  console.error(err);
+  process.exit(1);
+});